@@ -0,0 +1,73 @@
+package retry
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+/*
+	Adaptive adjusts the base interval of the Tryers it is attached to
+	according to how often their operations fail, using additive-increase,
+	multiplicative-decrease. Every failed attempt adds a fixed step to the
+	base interval and every successful attempt shrinks the extra interval
+	by a factor, so clients back off harder during sustained outages and
+	recover quickly once their dependency is healthy again.
+
+	An Adaptive is safe for concurrent use and may be shared by several
+	Tryers calling the same dependency. Attach it with Options.Adaptive.
+*/
+type Adaptive struct {
+	step  time.Duration
+	decay float64
+	max   time.Duration
+	mu    sync.Mutex
+	extra time.Duration
+}
+
+/*
+	NewAdaptive returns an Adaptive that adds step to the base interval for
+	every failed attempt, up to max, and multiplies the added interval by
+	decay for every successful attempt. Decay must be between 0 and 1; a
+	decay of 0.5 halves the added interval on each success.
+*/
+func NewAdaptive(step time.Duration, decay float64, max time.Duration) (*Adaptive, error) {
+
+	if step < 0 {
+		return nil, fmt.Errorf("expected step to be 0 or greater, got %s", step)
+	}
+
+	if decay < 0 || decay > 1 {
+		return nil, fmt.Errorf("expected a decay value between 0 and 1, got %.2f", decay)
+	}
+
+	if max < 0 {
+		return nil, fmt.Errorf("expected max to be 0 or greater, got %s", max)
+	}
+
+	return &Adaptive{step: step, decay: decay, max: max}, nil
+}
+
+/*
+	Extra returns the interval currently added to the base interval.
+*/
+func (a *Adaptive) Extra() time.Duration {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.extra
+}
+
+func (a *Adaptive) failure() {
+	a.mu.Lock()
+	a.extra += a.step
+	if a.extra > a.max {
+		a.extra = a.max
+	}
+	a.mu.Unlock()
+}
+
+func (a *Adaptive) success() {
+	a.mu.Lock()
+	a.extra = time.Duration(float64(a.extra) * a.decay)
+	a.mu.Unlock()
+}
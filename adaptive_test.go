@@ -0,0 +1,60 @@
+package retry
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAdaptive(t *testing.T) {
+
+	a, err := NewAdaptive(time.Millisecond, 0.5, time.Millisecond*3)
+	if err != nil {
+		t.Fatal("Failed to initialise Adaptive:\n    ", err.Error())
+	}
+
+	tryer, err := New(nil, Options{
+		Retries:     4,
+		Base:        time.Millisecond,
+		MaxInterval: time.Millisecond * 20,
+		MaxWait:     time.Second * 1,
+		Exponent:    1,
+		Adaptive:    a,
+	})
+	if err != nil {
+		t.Fatal("Failed to initialise Tryer while testing Adaptive:\n    ", err.Error())
+	}
+
+	// Five failures are capped at 3ms of extra interval.
+	_, _ = tryer.Try(func() error {
+		return errors.New("test")
+	})
+	if got := a.Extra(); got != time.Millisecond*3 {
+		t.Errorf("Adaptive.Extra() after failures = %s, wanted 3ms", got)
+	}
+	if got := tryer.Delay(0); got != time.Millisecond*4 {
+		t.Errorf("Tryer.Delay(0) after failures = %s, wanted 4ms", got)
+	}
+
+	// A success halves it.
+	_, _ = tryer.Try(func() error {
+		return nil
+	})
+	if got := a.Extra(); got != time.Microsecond*1500 {
+		t.Errorf("Adaptive.Extra() after success = %s, wanted 1.5ms", got)
+	}
+
+	for _, c := range []struct {
+		step  time.Duration
+		decay float64
+		max   time.Duration
+	}{
+		{-1, 0.5, 0},
+		{0, 1.5, 0},
+		{0, 0.5, -1},
+	} {
+		if _, err := NewAdaptive(c.step, c.decay, c.max); err == nil {
+			t.Errorf("NewAdaptive(%s, %.2f, %s) returned nil error, wanted error", c.step, c.decay, c.max)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package retry
+
+import "context"
+
+/*
+	RefreshAuth returns a Middleware covering the common flow of refreshing
+	expired credentials and trying again. When an attempt fails with an
+	error for which expired returns true, refresh is called and the attempt
+	is repeated straight away. The repeat does not wait for the backoff and
+	does not count against Options.Retries.
+
+	Refresh is called at most once per call to Try or TryContext. If it
+	fails its error is returned as the result of the attempt, and if the
+	repeated attempt also reports expired credentials the error is handled
+	like any other.
+*/
+func RefreshAuth(expired func(err error) bool, refresh func(ctx context.Context) error) Middleware {
+	return func(next OperationCtx) OperationCtx {
+		refreshed := false
+		return func(ctx context.Context) error {
+			err := next(ctx)
+			if err == nil || refreshed || !expired(err) {
+				return err
+			}
+			refreshed = true
+			if err := refresh(ctx); err != nil {
+				return err
+			}
+			return next(ctx)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRefreshAuth(t *testing.T) {
+
+	errExpired := errors.New("token expired")
+
+	cases := []struct {
+		name      string
+		results   []error // returned by successive attempts
+		wantErr   error
+		wantCalls int
+		wantRefs  int
+	}{
+		// Refreshing fixes the operation without using a retry.
+		{"refresh succeeds", []error{errExpired, nil}, nil, 2, 1},
+
+		// Refresh is only tried once per call.
+		{"still expired", []error{errExpired, errExpired}, ErrCancelled, 2, 1},
+
+		// Other errors never cause a refresh.
+		{"other error", []error{errors.New("test")}, ErrCancelled, 1, 0},
+	}
+
+	for _, c := range cases {
+
+		refreshes := 0
+		tryer, err := New(
+			func(error) bool { return false },
+			Options{
+				Base:        time.Millisecond * 5,
+				MaxInterval: time.Millisecond * 20,
+				MaxWait:     time.Second * 1,
+				Exponent:    2,
+				Middleware: []Middleware{RefreshAuth(
+					func(err error) bool { return errors.Is(err, errExpired) },
+					func(context.Context) error { refreshes++; return nil },
+				)},
+			})
+		if err != nil {
+			t.Fatal("Failed to initialise Tryer while testing RefreshAuth:\n    ", err.Error())
+		}
+
+		calls := 0
+		_, err = tryer.Try(func() error {
+			calls++
+			return c.results[calls-1]
+		})
+
+		if !errors.Is(err, c.wantErr) || calls != c.wantCalls || refreshes != c.wantRefs {
+			t.Errorf(
+				"%s:\n"+
+					"    got err %v, %d calls, %d refreshes\n"+
+					"    wanted %v, %d calls, %d refreshes\n",
+				c.name, err, calls, refreshes, c.wantErr, c.wantCalls, c.wantRefs)
+		}
+	}
+}
@@ -0,0 +1,18 @@
+package retry
+
+import "time"
+
+/*
+	Backoff computes the delays between the attempts of a single call to
+	Try, in place of the exponential backoff described by Options. Next is
+	called with the error from each failed attempt that may be retried and
+	returns the delay before the next attempt, or false to stop retrying,
+	in which case Try returns ErrMaxRetries. The error lets a Backoff wait
+	longer for some failures, such as being throttled by a server.
+
+	A Backoff is only used by one call to Try at a time, so it may keep
+	state without synchronisation. See Options.Backoff.
+*/
+type Backoff interface {
+	Next(err error) (delay time.Duration, ok bool)
+}
@@ -0,0 +1,129 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func benchmarkTryer(b *testing.B) *Tryer {
+	tryer, err := New(nil, Options{
+		Retries:     3,
+		Base:        time.Nanosecond,
+		MaxInterval: time.Nanosecond,
+		MaxWait:     time.Second * 1,
+		Exponent:    2,
+		Jitter:      0.5,
+	})
+	if err != nil {
+		b.Fatal("Failed to initialise Tryer while benchmarking:\n    ", err.Error())
+	}
+	return tryer
+}
+
+/*
+	TestAllocs fails if a change adds allocations to Try. The figures
+	exclude the closures made by the caller, which the benchmarks count.
+*/
+func TestAllocs(t *testing.T) {
+
+	tryer, err := New(nil, Options{
+		Retries:     3,
+		Base:        time.Nanosecond,
+		MaxInterval: time.Nanosecond,
+		MaxWait:     time.Second * 1,
+		Exponent:    2,
+		Jitter:      0.5,
+	})
+	if err != nil {
+		t.Fatal("Failed to initialise Tryer while testing allocations:\n    ", err.Error())
+	}
+
+	ctx := context.Background()
+	errTest := errors.New("test")
+	attempts := 0
+	succeed := func() error { return nil }
+	succeedCtx := func(context.Context) error { return nil }
+	failTwice := func(context.Context) error {
+		attempts++
+		if attempts < 3 {
+			return errTest
+		}
+		return nil
+	}
+
+	cases := []struct {
+		name string
+		run  func()
+		max  float64
+	}{
+		{"Try", func() { tryer.Try(succeed) }, 0},
+		{"TryContext", func() { tryer.TryContext(ctx, succeedCtx) }, 1},
+		{"TryContext with two retries", func() {
+			attempts = 0
+			tryer.TryContext(ctx, failTwice)
+		}, 7},
+	}
+
+	for _, c := range cases {
+		if n := testing.AllocsPerRun(100, c.run); n > c.max {
+			t.Errorf("%s made %v allocations, wanted at most %v", c.name, n, c.max)
+		}
+	}
+}
+
+func BenchmarkTrySuccess(b *testing.B) {
+	tryer := benchmarkTryer(b)
+	fn := func() error { return nil }
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		tryer.Try(fn)
+	}
+}
+
+func BenchmarkTryContextSuccess(b *testing.B) {
+	tryer := benchmarkTryer(b)
+	ctx := context.Background()
+	fn := func(context.Context) error { return nil }
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		tryer.TryContext(ctx, fn)
+	}
+}
+
+func BenchmarkTryContextRetry(b *testing.B) {
+	tryer := benchmarkTryer(b)
+	ctx := context.Background()
+	errTest := errors.New("test")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		attempts := 0
+		tryer.TryContext(ctx, func(context.Context) error {
+			attempts++
+			if attempts < 3 {
+				return errTest
+			}
+			return nil
+		})
+	}
+}
+
+func BenchmarkTryContextRetryParallel(b *testing.B) {
+	tryer := benchmarkTryer(b)
+	ctx := context.Background()
+	errTest := errors.New("test")
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			attempts := 0
+			tryer.TryContext(ctx, func(context.Context) error {
+				attempts++
+				if attempts < 3 {
+					return errTest
+				}
+				return nil
+			})
+		}
+	})
+}
@@ -0,0 +1,154 @@
+package retry
+
+import "time"
+
+/*
+	Builder assembles Options step by step, starting from defaults that
+	suit most network calls, and validates them when the Tryer is made.
+	It saves filling in every field of a bare Options, where a forgotten
+	MaxWait or Exponent is only noticed when New fails or Try gives up
+	at once:
+
+		t := retry.Build().
+			Exponential(time.Millisecond*50, time.Second).
+			Retries(5).
+			Jitter(0.5).
+			MaxWait(time.Second * 2).
+			MustTryer()
+
+	The defaults are those returned by DefaultOptions. Later calls
+	override earlier ones.
+*/
+type Builder struct {
+	retry Retry
+	opts  Options
+}
+
+/*
+	DefaultOptions returns the Options a Builder starts from: 3 retries
+	with delays starting at 100ms, doubling up to 5s, jittered by half,
+	giving up after 30s.
+*/
+func DefaultOptions() Options {
+	return Options{
+		Retries:     3,
+		Base:        time.Millisecond * 100,
+		MaxInterval: time.Second * 5,
+		MaxWait:     time.Second * 30,
+		Exponent:    2,
+		Jitter:      0.5,
+	}
+}
+
+/*
+	Build returns a Builder starting from DefaultOptions.
+*/
+func Build() *Builder {
+	return &Builder{opts: DefaultOptions()}
+}
+
+/*
+	Exponential sets delays to start at base and double after each retry
+	up to max.
+*/
+func (b *Builder) Exponential(base, max time.Duration) *Builder {
+	b.opts.Base = base
+	b.opts.MaxInterval = max
+	b.opts.Exponent = 2
+	return b
+}
+
+/*
+	Constant sets every delay to d, before jitter.
+*/
+func (b *Builder) Constant(d time.Duration) *Builder {
+	b.opts.Base = d
+	b.opts.MaxInterval = d
+	b.opts.Exponent = 1
+	return b
+}
+
+/*
+	Retries sets Options.Retries and clears Options.MaxAttempts, since
+	only one of them may be set.
+*/
+func (b *Builder) Retries(n int) *Builder {
+	b.opts.Retries = n
+	b.opts.MaxAttempts = 0
+	return b
+}
+
+/*
+	MaxAttempts sets Options.MaxAttempts and clears Options.Retries, since
+	only one of them may be set.
+*/
+func (b *Builder) MaxAttempts(n int) *Builder {
+	b.opts.MaxAttempts = n
+	b.opts.Retries = 0
+	return b
+}
+
+/*
+	Jitter sets Options.Jitter.
+*/
+func (b *Builder) Jitter(j float64) *Builder {
+	b.opts.Jitter = j
+	return b
+}
+
+/*
+	MaxWait sets Options.MaxWait.
+*/
+func (b *Builder) MaxWait(d time.Duration) *Builder {
+	b.opts.MaxWait = d
+	return b
+}
+
+/*
+	AttemptTimeout sets Options.AttemptTimeout.
+*/
+func (b *Builder) AttemptTimeout(d time.Duration) *Builder {
+	b.opts.AttemptTimeout = d
+	return b
+}
+
+/*
+	Retry sets the Retry passed to New, which decides which errors are
+	retried. By default all errors are.
+*/
+func (b *Builder) Retry(r Retry) *Builder {
+	b.retry = r
+	return b
+}
+
+/*
+	With calls fn to set any fields of the Options that the Builder has
+	no method for, such as hooks.
+*/
+func (b *Builder) With(fn func(o *Options)) *Builder {
+	fn(&b.opts)
+	return b
+}
+
+/*
+	Options returns the Options built so far, without validating them.
+*/
+func (b *Builder) Options() Options {
+	return b.opts
+}
+
+/*
+	Tryer returns a new Tryer made with New from the Builder's Retry and
+	Options.
+*/
+func (b *Builder) Tryer() (*Tryer, error) {
+	return New(b.retry, b.opts)
+}
+
+/*
+	MustTryer is like Tryer but panics if the Options are invalid. It is
+	meant for policies fixed in code, where invalid Options are a bug.
+*/
+func (b *Builder) MustTryer() *Tryer {
+	return Must(b.Tryer())
+}
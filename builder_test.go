@@ -0,0 +1,58 @@
+package retry
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBuilder(t *testing.T) {
+
+	if _, err := Build().Tryer(); err != nil {
+		t.Errorf("Build().Tryer() with DefaultOptions returned %v, wanted nil", err)
+	}
+
+	errPermanent := errors.New("permanent")
+	tryer := Build().
+		Exponential(time.Millisecond*1, time.Millisecond*5).
+		Retries(2).
+		Jitter(0).
+		MaxWait(time.Second).
+		Retry(func(err error) bool { return err != errPermanent }).
+		With(func(o *Options) { o.MinInterval = time.Millisecond }).
+		MustTryer()
+
+	o := tryer.Options()
+	if o.Base != time.Millisecond || o.MaxInterval != time.Millisecond*5 || o.Exponent != 2 ||
+		o.Retries != 2 || o.Jitter != 0 || o.MaxWait != time.Second || o.MinInterval != time.Millisecond {
+		t.Errorf("Builder produced %+v", o)
+	}
+
+	calls := 0
+	_, err := tryer.Try(func() error {
+		calls++
+		return errPermanent
+	})
+	if !errors.Is(err, ErrCancelled) || calls != 1 {
+		t.Errorf("Try with Builder's Retry returned %v after %d calls, wanted %v after 1", err, calls, ErrCancelled)
+	}
+
+	if o := Build().Constant(time.Second).Options(); o.Base != time.Second || o.MaxInterval != time.Second || o.Exponent != 1 {
+		t.Errorf("Constant produced %+v", o)
+	}
+
+	// MaxAttempts and Retries replace each other rather than conflict.
+	if tr, err := Build().MaxAttempts(5).Tryer(); err != nil || tr.Options().RetryLimit() != 4 {
+		t.Errorf("Build().MaxAttempts(5).Tryer() returned %v, wanted a Tryer allowing 4 retries", err)
+	}
+	if tr, err := Build().MaxAttempts(5).Retries(2).Tryer(); err != nil || tr.Options().RetryLimit() != 2 {
+		t.Errorf("Build().MaxAttempts(5).Retries(2).Tryer() returned %v, wanted a Tryer allowing 2 retries", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("MustTryer with invalid Options didn't panic")
+		}
+	}()
+	Build().Jitter(2).MustTryer()
+}
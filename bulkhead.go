@@ -0,0 +1,65 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+/*
+	ErrBulkheadFull is returned by attempts rejected by a Bulkhead because
+	its dependency already has the maximum number of attempts in flight.
+*/
+var ErrBulkheadFull = errors.New("too many attempts in flight")
+
+/*
+	Bulkhead limits the number of attempts in flight to one dependency, so
+	a slow dependency being retried can't tie up every connection or
+	goroutine in a service. Attempts made while the limit is reached fail
+	straight away with ErrBulkheadFull rather than queueing, and are
+	retried after the usual backoff unless the Retry passed to New says
+	otherwise.
+
+	A Bulkhead is safe for concurrent use and is usually shared by all the
+	Tryers calling one dependency, with a separate Bulkhead for each
+	dependency. Attach it with its Middleware method.
+*/
+type Bulkhead struct {
+	slots chan struct{}
+}
+
+/*
+	NewBulkhead returns a Bulkhead allowing at most limit attempts in
+	flight. limit must be greater than 0.
+*/
+func NewBulkhead(limit int) (*Bulkhead, error) {
+	if limit <= 0 {
+		return nil, fmt.Errorf("expected limit to be greater than 0, got %d", limit)
+	}
+	return &Bulkhead{slots: make(chan struct{}, limit)}, nil
+}
+
+/*
+	InFlight returns the number of attempts currently holding a slot.
+*/
+func (b *Bulkhead) InFlight() int {
+	return len(b.slots)
+}
+
+/*
+	Middleware returns a Middleware that runs each attempt in a slot of b,
+	for use in Options.Middleware.
+*/
+func (b *Bulkhead) Middleware() Middleware {
+	return func(next OperationCtx) OperationCtx {
+		return func(ctx context.Context) error {
+			select {
+			case b.slots <- struct{}{}:
+			default:
+				return ErrBulkheadFull
+			}
+			defer func() { <-b.slots }()
+			return next(ctx)
+		}
+	}
+}
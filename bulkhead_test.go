@@ -0,0 +1,76 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBulkhead(t *testing.T) {
+
+	if _, err := NewBulkhead(0); err == nil {
+		t.Errorf("NewBulkhead(0) returned nil error, wanted error")
+	}
+
+	b, err := NewBulkhead(2)
+	if err != nil {
+		t.Fatal("Failed to initialise Bulkhead:\n    ", err.Error())
+	}
+
+	tryer, err := New(
+		func(err error) bool { return false },
+		Options{
+			Base:        time.Millisecond * 1,
+			MaxInterval: time.Millisecond * 5,
+			MaxWait:     time.Second * 1,
+			Exponent:    2,
+			Middleware:  []Middleware{b.Middleware()},
+		})
+	if err != nil {
+		t.Fatal("Failed to initialise Tryer while testing Bulkhead:\n    ", err.Error())
+	}
+
+	// Fill the bulkhead with two attempts that block until released.
+	release := make(chan struct{})
+	started := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tryer.TryContext(context.Background(), func(context.Context) error {
+				started <- struct{}{}
+				<-release
+				return nil
+			})
+		}()
+	}
+	<-started
+	<-started
+
+	if n := b.InFlight(); n != 2 {
+		t.Errorf("InFlight() = %d, wanted 2", n)
+	}
+
+	calls := 0
+	errs, err := tryer.TryContext(context.Background(), func(context.Context) error {
+		calls++
+		return nil
+	})
+	if calls != 0 || len(errs) != 1 || !errors.Is(errs[0], ErrBulkheadFull) {
+		t.Errorf("TryContext on full Bulkhead made %d calls and returned %v, %v, wanted 0 calls and %v",
+			calls, errs, err, ErrBulkheadFull)
+	}
+
+	close(release)
+	wg.Wait()
+
+	if n := b.InFlight(); n != 0 {
+		t.Errorf("InFlight() after release = %d, wanted 0", n)
+	}
+	if _, err := tryer.TryContext(context.Background(), func(context.Context) error { return nil }); err != nil {
+		t.Errorf("TryContext after release returned %v, wanted nil", err)
+	}
+}
@@ -0,0 +1,53 @@
+package retry
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBurnRate(t *testing.T) {
+
+	cases := []struct {
+		burn      float64
+		wantErr   error
+		wantCalls int
+		wantWait  time.Duration // least time waited over the call
+	}{
+		{0, ErrMaxRetries, 5, time.Millisecond * 40},
+		{1, ErrMaxRetries, 5, time.Millisecond * 40},
+		{2, ErrThrottled, 3, time.Millisecond * 40},
+		{4, ErrThrottled, 2, time.Millisecond * 40},
+		{10, ErrThrottled, 1, 0},
+	}
+
+	for _, c := range cases {
+
+		tryer, err := New(nil, Options{
+			Retries:     4,
+			Base:        time.Millisecond * 10,
+			MaxInterval: time.Millisecond * 10,
+			MaxWait:     time.Second * 1,
+			Exponent:    1,
+			BurnRate:    func() float64 { return c.burn },
+		})
+		if err != nil {
+			t.Fatal("Failed to initialise Tryer while testing BurnRate:\n    ", err.Error())
+		}
+
+		calls := 0
+		start := time.Now()
+		_, err = tryer.Try(func() error {
+			calls++
+			return errors.New("test")
+		})
+
+		if !errors.Is(err, c.wantErr) || calls != c.wantCalls {
+			t.Errorf("Try with burn rate %.0f returned %v after %d calls, wanted %v after %d",
+				c.burn, err, calls, c.wantErr, c.wantCalls)
+		}
+		if d := time.Since(start); d < c.wantWait {
+			t.Errorf("Try with burn rate %.0f returned after %s, wanted at least %s", c.burn, d, c.wantWait)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+/*
+Command retry runs a command, retrying it with exponential backoff and
+jitter until it succeeds.
+
+Usage:
+
+	retry [flags] -- command [args...]
+
+For example, to retry a flaky download up to 5 times, only on curl's exit
+codes for failed connections and timeouts:
+
+	retry -retries 5 -base 1s -max-wait 1m -retry-on 7,28 -- curl -fsSO https://example.com/file
+
+The flags are:
+
+	-policy string
+		A policy string such as "exponential(base=50ms, retries=3)", as
+		read by retry.Parse. It overrides the defaults of the fields it
+		names, and other flags override it in turn.
+	-retries int
+		Maximum number of retries after the first attempt. (default 3)
+	-base duration
+		Delay before the first retry. (default 1s)
+	-max-interval duration
+		Longest delay between attempts. (default 30s)
+	-max-wait duration
+		Most time to spend running the command. (default 5m)
+	-exponent float
+		Growth rate of the delay. (default 2)
+	-jitter float
+		Randomness of the delay between 0 and 1. (default 0.5)
+	-retry-on codes
+		Comma separated exit codes to retry. Defaults to any non-zero code.
+	-abort-on codes
+		Comma separated exit codes never to retry.
+	-capture
+		Buffer the output of each attempt and only print the output of the
+		last one, instead of streaming the output of every attempt.
+	-v
+		Print each failed attempt and the delay before the next one to
+		standard error.
+
+Retry exits with the exit code of the last attempt, or 1 if the command
+could not be run at all.
+*/
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jakebowkett/retry"
+	"github.com/jakebowkett/retry/retryexec"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+
+	fs := flag.NewFlagSet("retry", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: retry [flags] -- command [args...]")
+		fs.PrintDefaults()
+	}
+
+	o := retry.Options{
+		Retries:     3,
+		Base:        time.Second,
+		MaxInterval: time.Second * 30,
+		MaxWait:     time.Minute * 5,
+		Exponent:    2,
+		Jitter:      0.5,
+	}
+
+	var retryOn, abortOn codes
+	fs.Func("policy", "policy string such as \"exponential(base=50ms, retries=3)\"", func(s string) error {
+		return o.UnmarshalText([]byte(s))
+	})
+	retries := fs.Int("retries", o.Retries, "maximum number of retries after the first attempt")
+	base := fs.Duration("base", o.Base, "delay before the first retry")
+	maxInterval := fs.Duration("max-interval", o.MaxInterval, "longest delay between attempts")
+	maxWait := fs.Duration("max-wait", o.MaxWait, "most time to spend running the command")
+	exponent := fs.Float64("exponent", o.Exponent, "growth rate of the delay")
+	jitter := fs.Float64("jitter", o.Jitter, "randomness of the delay between 0 and 1")
+	fs.Var(&retryOn, "retry-on", "comma separated exit codes to retry (default any non-zero code)")
+	fs.Var(&abortOn, "abort-on", "comma separated exit codes never to retry")
+	capture := fs.Bool("capture", false, "only print the output of the last attempt")
+	verbose := fs.Bool("v", false, "print failed attempts to standard error")
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	// Flags given explicitly override the policy.
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "retries":
+			o.Retries = *retries
+			o.MaxAttempts = 0
+		case "base":
+			o.Base = *base
+		case "max-interval":
+			o.MaxInterval = *maxInterval
+		case "max-wait":
+			o.MaxWait = *maxWait
+		case "exponent":
+			o.Exponent = *exponent
+		case "jitter":
+			o.Jitter = *jitter
+		}
+	})
+
+	if *verbose {
+		o.OnRetry = func(s retry.State, err error) {
+			fmt.Fprintf(stderr, "retry: attempt %d failed: %v; retrying in %s\n",
+				s.Attempts, err, s.NextDelay.Round(time.Millisecond))
+		}
+	}
+
+	t, err := retry.New(retryexec.ExitCodes(retryOn, abortOn), o)
+	if err != nil {
+		fmt.Fprintln(stderr, "retry:", err)
+		return 2
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	name, cmdArgs := fs.Arg(0), fs.Args()[1:]
+	var out, errOut bytes.Buffer
+
+	err = retryexec.RunCmd(ctx, t, func(ctx context.Context) *exec.Cmd {
+		cmd := exec.CommandContext(ctx, name, cmdArgs...)
+		cmd.Stdin = os.Stdin
+		cmd.Stdout, cmd.Stderr = stdout, stderr
+		if *capture {
+			out.Reset()
+			errOut.Reset()
+			cmd.Stdout, cmd.Stderr = &out, &errOut
+		}
+		return cmd
+	})
+
+	if *capture {
+		io.Copy(stdout, &out)
+		io.Copy(stderr, &errOut)
+	}
+
+	if err == nil {
+		return 0
+	}
+
+	if code, ok := retryexec.ExitCode(err); ok {
+		if *verbose {
+			fmt.Fprintln(stderr, "retry:", err)
+		}
+		return code
+	}
+	fmt.Fprintln(stderr, "retry:", err)
+	return 1
+}
+
+/*
+	codes is a flag.Value holding a comma separated list of exit codes.
+*/
+type codes []int
+
+func (c *codes) String() string {
+	s := make([]string, len(*c))
+	for i, code := range *c {
+		s[i] = strconv.Itoa(code)
+	}
+	return strings.Join(s, ",")
+}
+
+func (c *codes) Set(v string) error {
+	for _, s := range strings.Split(v, ",") {
+		code, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil {
+			return fmt.Errorf("expected comma separated exit codes, got %q", v)
+		}
+		*c = append(*c, code)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	cases := []struct {
+		args     []string
+		wantCode int
+		wantOut  string
+	}{
+		// Succeeds first time.
+		{[]string{"--", "sh", "-c", "echo ok"}, 0, "ok\n"},
+
+		// Always fails so every attempt's output is printed.
+		{[]string{"-retries", "2", "-base", "1ms", "--", "sh", "-c", "echo no; exit 3"}, 3, "no\nno\nno\n"},
+
+		// Captured output only shows the last attempt.
+		{[]string{"-retries", "2", "-base", "1ms", "-capture", "--", "sh", "-c", "echo no; exit 3"}, 3, "no\n"},
+
+		// Exit code 3 isn't in -retry-on so isn't retried.
+		{[]string{"-retries", "2", "-base", "1ms", "-retry-on", "75", "--", "sh", "-c", "echo no; exit 3"}, 3, "no\n"},
+
+		// Exit code 2 is in -abort-on so isn't retried.
+		{[]string{"-retries", "2", "-base", "1ms", "-abort-on", "2", "--", "sh", "-c", "echo no; exit 2"}, 2, "no\n"},
+
+		// A policy keeps the defaults of the fields it doesn't name.
+		{[]string{"-policy", "exponential(base=50ms, retries=3)", "--", "sh", "-c", "echo ok"}, 0, "ok\n"},
+		{[]string{"-policy", "exponential(base=1ms, retries=2)", "--", "sh", "-c", "echo no; exit 3"}, 3, "no\nno\nno\n"},
+
+		// Flags override the policy.
+		{[]string{"-policy", "exponential(base=1ms, retries=5)", "-retries", "1", "--", "sh", "-c", "echo no; exit 3"}, 3, "no\nno\n"},
+
+		// Bad flags and missing command.
+		{[]string{"-retry-on", "x", "--", "true"}, 2, ""},
+		{[]string{"-retries", "1"}, 2, ""},
+		{[]string{"-policy", "linear(base=1ms)", "--", "true"}, 2, ""},
+	}
+
+	for _, c := range cases {
+		var out, errOut bytes.Buffer
+		code := run(c.args, &out, &errOut)
+		if code != c.wantCode || out.String() != c.wantOut {
+			t.Errorf(
+				"retry %s\n"+
+					"    exit %d, output %q\n"+
+					"    wanted %d, %q\n",
+				strings.Join(c.args, " "), code, out.String(), c.wantCode, c.wantOut)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package retry
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+/*
+	ErrorClass describes how errors with a given code should be retried.
+	See Codes.
+*/
+type ErrorClass struct {
+	/*
+		Retry reports whether errors in the class are worth retrying.
+	*/
+	Retry bool
+
+	/*
+		MinDelay is the least time to wait before retrying an error in the
+		class, for example a long one for codes meaning the caller is rate
+		limited. It only applies to Tryers using Codes.Middleware.
+	*/
+	MinDelay time.Duration
+}
+
+/*
+	Codes maps the codes of an organisation's internal error taxonomy,
+	such as "UNAVAILABLE" or "quota_exceeded", to ErrorClasses, so that
+	retry semantics can be defined once and shared by every Tryer. The
+	code of an error is found by a function supplied to NewCodes. A Codes
+	is safe for concurrent use, so classes may be defined at any time.
+*/
+type Codes struct {
+	codeOf  func(err error) string
+	unknown ErrorClass
+	mu      sync.RWMutex
+	classes map[string]ErrorClass
+}
+
+/*
+	NewCodes returns a Codes that finds the code of an error with codeOf,
+	which should return "" for errors without one. Errors whose codes have
+	no class defined are given the class unknown.
+*/
+func NewCodes(codeOf func(err error) string, unknown ErrorClass) *Codes {
+	return &Codes{
+		codeOf:  codeOf,
+		unknown: unknown,
+		classes: map[string]ErrorClass{},
+	}
+}
+
+/*
+	Define sets the class of errors with the given code, replacing any
+	class defined for it before.
+*/
+func (c *Codes) Define(code string, class ErrorClass) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.classes[code] = class
+}
+
+/*
+	Class returns the class of err according to its code.
+*/
+func (c *Codes) Class(err error) ErrorClass {
+	code := c.codeOf(err)
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if class, ok := c.classes[code]; ok {
+		return class
+	}
+	return c.unknown
+}
+
+/*
+	Retry reports whether the class of err allows it to be retried. It is
+	suitable for passing to New.
+*/
+func (c *Codes) Retry(err error) bool {
+	return c.Class(err).Retry
+}
+
+/*
+	Middleware returns a Middleware that makes Try wait at least the
+	MinDelay of the class of each failed attempt's error before retrying
+	it, by wrapping the error with WithHint.
+*/
+func (c *Codes) Middleware() Middleware {
+	return func(next OperationCtx) OperationCtx {
+		return func(ctx context.Context) error {
+			err := next(ctx)
+			if err == nil {
+				return nil
+			}
+			if d := c.Class(err).MinDelay; d > 0 {
+				return WithHint(err, Hint{Delay: d})
+			}
+			return err
+		}
+	}
+}
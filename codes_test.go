@@ -0,0 +1,77 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+/*
+	codedError has a code like the errors of an internal taxonomy.
+*/
+type codedError struct{ code string }
+
+func (e *codedError) Error() string { return "failed with " + e.code }
+
+func codeOf(err error) string {
+	var ce *codedError
+	if errors.As(err, &ce) {
+		return ce.code
+	}
+	return ""
+}
+
+func TestCodes(t *testing.T) {
+
+	codes := NewCodes(codeOf, ErrorClass{Retry: true})
+	codes.Define("NOT_FOUND", ErrorClass{Retry: false})
+	codes.Define("RATE_LIMITED", ErrorClass{Retry: true, MinDelay: time.Millisecond * 40})
+
+	cases := []struct {
+		err       error
+		wantCalls int
+		wantDelay time.Duration // least delay before the retry
+	}{
+		{errors.New("test"), 2, 0},
+		{&codedError{"UNAVAILABLE"}, 2, 0},
+		{&codedError{"NOT_FOUND"}, 1, 0},
+		{&codedError{"RATE_LIMITED"}, 2, time.Millisecond * 40},
+	}
+
+	for _, c := range cases {
+
+		tryer, err := New(codes.Retry, Options{
+			Retries:     1,
+			Base:        time.Millisecond * 1,
+			MaxInterval: time.Millisecond * 5,
+			MaxWait:     time.Second * 1,
+			Exponent:    2,
+			Middleware:  []Middleware{codes.Middleware()},
+		})
+		if err != nil {
+			t.Fatal("Failed to initialise Tryer while testing Codes:\n    ", err.Error())
+		}
+
+		calls := 0
+		var first time.Time
+		errs, _ := tryer.TryContext(context.Background(), func(ctx context.Context) error {
+			calls++
+			if calls == 1 {
+				first = time.Now()
+				return c.err
+			}
+			if d := time.Since(first); d < c.wantDelay {
+				t.Errorf("Try with %v retried after %s, wanted at least %s", c.err, d, c.wantDelay)
+			}
+			return nil
+		})
+
+		if calls != c.wantCalls {
+			t.Errorf("Try with %v made %d calls, wanted %d", c.err, calls, c.wantCalls)
+		}
+		if len(errs) > 0 && !errors.Is(errs[0], c.err) {
+			t.Errorf("Try with %v recorded error %v", c.err, errs[0])
+		}
+	}
+}
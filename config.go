@@ -0,0 +1,192 @@
+package retry
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+/*
+	optionsJSON is the JSON representation of the fields of Options that
+	can be configured from data rather than code. Pointers distinguish
+	missing fields from zero values so UnmarshalJSON leaves missing fields
+	untouched, like encoding/json does for ordinary structs.
+*/
+type optionsJSON struct {
+	Retries        *int      `json:"retries,omitempty"`
+	MaxAttempts    *int      `json:"maxAttempts,omitempty"`
+	Base           *duration `json:"base"`
+	MaxInterval    *duration `json:"maxInterval"`
+	MinInterval    *duration `json:"minInterval,omitempty"`
+	MaxWait        *duration `json:"maxWait"`
+	AttemptTimeout *duration `json:"attemptTimeout,omitempty"`
+	Exponent       *float64  `json:"exponent"`
+	MaxExponent    *float64  `json:"maxExponent,omitempty"`
+	Jitter         *float64  `json:"jitter"`
+}
+
+/*
+	duration is a time.Duration that unmarshals from either a string
+	understood by time.ParseDuration, such as "50ms", or a number of
+	nanoseconds, and marshals to a string.
+*/
+type duration time.Duration
+
+func (d duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *duration) UnmarshalJSON(b []byte) error {
+
+	if len(b) > 0 && b[0] == '"' {
+		var s string
+		if err := json.Unmarshal(b, &s); err != nil {
+			return err
+		}
+		v, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		*d = duration(v)
+		return nil
+	}
+
+	var n int64
+	if err := json.Unmarshal(b, &n); err != nil {
+		return fmt.Errorf("expected a duration such as \"50ms\" or a number of nanoseconds, got %s", b)
+	}
+	*d = duration(n)
+	return nil
+}
+
+/*
+	UnmarshalJSON sets the fields of o from a JSON object such as:
+
+		{
+			"retries":        3,
+			"base":           "50ms",
+			"maxInterval":    "1s",
+			"minInterval":    "10ms",
+			"maxWait":        "2s",
+			"attemptTimeout": "500ms",
+			"exponent":       2,
+			"maxExponent":    3,
+			"jitter":         0.5
+		}
+
+	Durations may be strings understood by time.ParseDuration or numbers
+	of nanoseconds. Fields missing from the object are left unchanged, as
+	are the hooks and other fields of o that can't be represented in JSON.
+	"maxAttempts" may be given instead of "retries" to set MaxAttempts, in
+	which case Retries is set to 0, and the reverse.
+*/
+func (o *Options) UnmarshalJSON(b []byte) error {
+
+	var j optionsJSON
+	if err := json.Unmarshal(b, &j); err != nil {
+		return err
+	}
+
+	if j.Retries != nil {
+		o.Retries = *j.Retries
+		if j.MaxAttempts == nil {
+			o.MaxAttempts = 0
+		}
+	}
+	if j.MaxAttempts != nil {
+		o.MaxAttempts = *j.MaxAttempts
+		if j.Retries == nil {
+			o.Retries = 0
+		}
+	}
+	if j.Base != nil {
+		o.Base = time.Duration(*j.Base)
+	}
+	if j.MaxInterval != nil {
+		o.MaxInterval = time.Duration(*j.MaxInterval)
+	}
+	if j.MinInterval != nil {
+		o.MinInterval = time.Duration(*j.MinInterval)
+	}
+	if j.MaxWait != nil {
+		o.MaxWait = time.Duration(*j.MaxWait)
+	}
+	if j.AttemptTimeout != nil {
+		o.AttemptTimeout = time.Duration(*j.AttemptTimeout)
+	}
+	if j.Exponent != nil {
+		o.Exponent = *j.Exponent
+	}
+	if j.MaxExponent != nil {
+		o.MaxExponent = *j.MaxExponent
+	}
+	if j.Jitter != nil {
+		o.Jitter = *j.Jitter
+	}
+
+	return nil
+}
+
+/*
+	MarshalJSON encodes the fields of o that can be represented in JSON in
+	the format read by UnmarshalJSON, with durations written as strings.
+	MinInterval, AttemptTimeout and MaxExponent are omitted when they are
+	0. When MaxAttempts is set it is written as "maxAttempts" in place of
+	"retries". The fields Options.String leaves out are left out here too.
+*/
+func (o Options) MarshalJSON() ([]byte, error) {
+
+	base := duration(o.Base)
+	maxInterval := duration(o.MaxInterval)
+	maxWait := duration(o.MaxWait)
+
+	j := optionsJSON{
+		Base:        &base,
+		MaxInterval: &maxInterval,
+		MaxWait:     &maxWait,
+		Exponent:    &o.Exponent,
+		Jitter:      &o.Jitter,
+	}
+	if o.MaxAttempts != 0 {
+		j.MaxAttempts = &o.MaxAttempts
+	} else {
+		j.Retries = &o.Retries
+	}
+	if o.MinInterval != 0 {
+		minInterval := duration(o.MinInterval)
+		j.MinInterval = &minInterval
+	}
+	if o.AttemptTimeout != 0 {
+		attemptTimeout := duration(o.AttemptTimeout)
+		j.AttemptTimeout = &attemptTimeout
+	}
+	if o.MaxExponent != 0 {
+		j.MaxExponent = &o.MaxExponent
+	}
+
+	return json.Marshal(j)
+}
+
+/*
+	FromConfig returns the Options described by the JSON object in data,
+	for example the contents of a configuration file. See Options.UnmarshalJSON
+	for the format. Unlike UnmarshalJSON, FromConfig returns an error for
+	unknown fields so that typos in configuration files don't go unnoticed.
+	The Options are not validated until they are passed to New.
+*/
+func FromConfig(data []byte) (Options, error) {
+
+	var j optionsJSON
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&j); err != nil {
+		return Options{}, fmt.Errorf("couldn't parse retry config: %w", err)
+	}
+
+	var o Options
+	if err := o.UnmarshalJSON(data); err != nil {
+		return Options{}, fmt.Errorf("couldn't parse retry config: %w", err)
+	}
+	return o, nil
+}
@@ -0,0 +1,141 @@
+package retry
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestFromConfig(t *testing.T) {
+
+	cases := []struct {
+		config  string
+		wantErr bool
+		want    Options
+	}{
+		/*
+		   Should return errors.
+		*/
+
+		// Not JSON.
+		{`retries: 3`, true, Options{}},
+
+		// Unknown field.
+		{`{"retry": 3}`, true, Options{}},
+
+		// Malformed duration.
+		{`{"base": "50 milliseconds"}`, true, Options{}},
+
+		/*
+		   Should not return errors.
+		*/
+		{`{
+			"retries":     3,
+			"base":        "50ms",
+			"maxInterval": "1s",
+			"minInterval": "10ms",
+			"maxWait":     2000000000,
+			"exponent":    2,
+			"jitter":      0.5
+		}`, false, Options{
+			Retries:     3,
+			Base:        time.Millisecond * 50,
+			MaxInterval: time.Second * 1,
+			MinInterval: time.Millisecond * 10,
+			MaxWait:     time.Second * 2,
+			Exponent:    2,
+			Jitter:      0.5,
+		}},
+		{`{"maxAttempts": 4, "base": "10ms"}`, false, Options{
+			MaxAttempts: 4,
+			Base:        time.Millisecond * 10,
+		}},
+		{`{"attemptTimeout": "500ms", "exponent": 1, "maxExponent": 3}`, false, Options{
+			AttemptTimeout: time.Millisecond * 500,
+			Exponent:       1,
+			MaxExponent:    3,
+		}},
+	}
+
+	for _, c := range cases {
+		got, err := FromConfig([]byte(c.config))
+		if c.wantErr != (err != nil) || !c.wantErr && !sameOptions(got, c.want) {
+			t.Errorf(
+				"FromConfig(%s)\n"+
+					"    return %+v, %v\n"+
+					"    wanted %+v, error: %t\n",
+				c.config, got, err, c.want, c.wantErr)
+		}
+	}
+
+	// UnmarshalJSON leaves missing fields alone.
+	o := Options{Retries: 5, Exponent: 2}
+	if err := json.Unmarshal([]byte(`{"base": "10ms"}`), &o); err != nil {
+		t.Fatal(err)
+	}
+	if o.Retries != 5 || o.Exponent != 2 || o.Base != time.Millisecond*10 {
+		t.Errorf("json.Unmarshal into Options = %+v, wanted Retries 5, Exponent 2, Base 10ms", o)
+	}
+
+	// Setting maxAttempts clears Retries so the two aren't both set.
+	if err := json.Unmarshal([]byte(`{"maxAttempts": 4}`), &o); err != nil {
+		t.Fatal(err)
+	}
+	if o.Retries != 0 || o.MaxAttempts != 4 {
+		t.Errorf("json.Unmarshal into Options = %+v, wanted Retries 0, MaxAttempts 4", o)
+	}
+}
+
+/*
+	sameOptions compares the fields of Options that can be configured
+	from data; Options itself isn't comparable because of its hooks.
+*/
+func sameOptions(a, b Options) bool {
+	return a.Retries == b.Retries &&
+		a.MaxAttempts == b.MaxAttempts &&
+		a.Base == b.Base &&
+		a.MaxInterval == b.MaxInterval &&
+		a.MinInterval == b.MinInterval &&
+		a.MaxWait == b.MaxWait &&
+		a.AttemptTimeout == b.AttemptTimeout &&
+		a.Exponent == b.Exponent &&
+		a.MaxExponent == b.MaxExponent &&
+		a.Jitter == b.Jitter
+}
+
+func TestMarshalJSON(t *testing.T) {
+
+	o := Options{
+		Retries:     3,
+		Base:        time.Millisecond * 50,
+		MaxInterval: time.Second * 1,
+		MaxWait:     time.Second * 2,
+		Exponent:    2,
+		Jitter:      0.5,
+	}
+
+	tryer, err := New(nil, o)
+	if err != nil {
+		t.Fatal("Failed to initialise Tryer while testing MarshalJSON:\n    ", err.Error())
+	}
+
+	b, err := json.Marshal(tryer)
+	want := `{"retries":3,"base":"50ms","maxInterval":"1s","maxWait":"2s","exponent":2,"jitter":0.5}`
+	if err != nil || string(b) != want {
+		t.Errorf("json.Marshal(Tryer)\n    return %s, %v\n    wanted %s, nil\n", b, err, want)
+	}
+
+	got, err := FromConfig(b)
+	if err != nil || !sameOptions(got, o) {
+		t.Errorf("FromConfig(%s)\n    return %+v, %v\n    wanted %+v, nil\n", b, got, err, o)
+	}
+
+	o.Retries, o.MaxAttempts = 0, 4
+	o.AttemptTimeout, o.MaxExponent = time.Millisecond*500, 3
+	b, err = json.Marshal(o)
+	want = `{"maxAttempts":4,"base":"50ms","maxInterval":"1s","maxWait":"2s",` +
+		`"attemptTimeout":"500ms","exponent":2,"maxExponent":3,"jitter":0.5}`
+	if err != nil || string(b) != want {
+		t.Errorf("json.Marshal(Options)\n    return %s, %v\n    wanted %s, nil\n", b, err, want)
+	}
+}
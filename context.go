@@ -0,0 +1,114 @@
+package retry
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type attemptKey struct{}
+
+/*
+	attemptInfo is the metadata TryContext attaches to the context it
+	passes to each call of its operation.
+*/
+type attemptInfo struct {
+	n       int
+	max     int
+	elapsed time.Duration
+}
+
+/*
+	attemptCtx carries attemptInfo for an attempt. It behaves like the
+	result of context.WithValue but costs one allocation rather than two,
+	as the value doesn't need to be boxed in an interface.
+
+	The attemptCtx of a call's first attempt also holds state shared by
+	all of the call's attempts, which the others reach through call. This
+	avoids a separate allocation for it.
+*/
+type attemptCtx struct {
+	context.Context
+	info attemptInfo
+	call *attemptCtx
+
+	mu   sync.Mutex
+	done map[*onceKey]bool // Used by OnceAcrossAttempts.
+}
+
+func (c *attemptCtx) Value(key any) any {
+	if key == (attemptKey{}) {
+		return c
+	}
+	return c.Context.Value(key)
+}
+
+/*
+	withAttempt returns an attemptCtx for an attempt of the call whose
+	first attempt had the attemptCtx call, or of a new call if call is nil.
+*/
+func withAttempt(ctx context.Context, a attemptInfo, call *attemptCtx) *attemptCtx {
+	c := &attemptCtx{Context: ctx, info: a, call: call}
+	if call == nil {
+		c.call = c
+	}
+	return c
+}
+
+func attemptFrom(ctx context.Context) (attemptInfo, bool) {
+	if c, ok := ctx.Value(attemptKey{}).(*attemptCtx); ok {
+		return c.info, true
+	}
+	return attemptInfo{}, false
+}
+
+/*
+	AttemptFromContext returns the current attempt number from a context
+	passed to an OperationCtx by TryContext. Attempts are numbered from 1.
+	The ok result is false if ctx did not come from TryContext.
+*/
+func AttemptFromContext(ctx context.Context) (n int, ok bool) {
+	a, ok := attemptFrom(ctx)
+	return a.n, ok
+}
+
+/*
+	MaxAttemptsFromContext returns the total number of attempts the current
+	TryContext call allows, i.e. Options.Retries plus the initial attempt.
+	The ok result is false if ctx did not come from TryContext.
+*/
+func MaxAttemptsFromContext(ctx context.Context) (n int, ok bool) {
+	a, ok := attemptFrom(ctx)
+	return a.max, ok
+}
+
+/*
+	ElapsedFromContext returns the time that had elapsed since the current
+	TryContext call began when the current attempt started. The ok result
+	is false if ctx did not come from TryContext.
+*/
+func ElapsedFromContext(ctx context.Context) (d time.Duration, ok bool) {
+	a, ok := attemptFrom(ctx)
+	return a.elapsed, ok
+}
+
+/*
+	FirstAttempt reports whether ctx belongs to the first attempt of a
+	call to TryContext, or didn't come from TryContext at all. Operations
+	can use it to perform side effects that mustn't be repeated, such as
+	emitting an analytics event, only once. Attempts of a call to Resume
+	continue the numbering of the call being resumed, so they are never
+	first attempts.
+*/
+func FirstAttempt(ctx context.Context) bool {
+	a, ok := attemptFrom(ctx)
+	return !ok || a.n <= 1
+}
+
+/*
+	IsRetry reports whether ctx belongs to an attempt of a call to
+	TryContext other than the first. It is the opposite of FirstAttempt.
+*/
+func IsRetry(ctx context.Context) bool {
+	return !FirstAttempt(ctx)
+}
@@ -0,0 +1,86 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAttemptFromContext(t *testing.T) {
+
+	if _, ok := AttemptFromContext(context.Background()); ok {
+		t.Error("AttemptFromContext(context.Background()) returned ok, wanted !ok")
+	}
+
+	tryer, err := New(nil, Options{
+		Retries:     2,
+		Base:        time.Millisecond * 5,
+		MaxInterval: time.Millisecond * 20,
+		MaxWait:     time.Second * 1,
+		Exponent:    2,
+	})
+	if err != nil {
+		t.Fatal("Failed to initialise Tryer while testing AttemptFromContext:\n    ", err.Error())
+	}
+
+	var got []int
+	var last time.Duration
+	_, _ = tryer.TryContext(context.Background(), func(ctx context.Context) error {
+
+		n, ok := AttemptFromContext(ctx)
+		if !ok {
+			t.Fatal("AttemptFromContext returned !ok inside TryContext")
+		}
+		got = append(got, n)
+
+		if max, _ := MaxAttemptsFromContext(ctx); max != 3 {
+			t.Errorf("MaxAttemptsFromContext = %d, wanted 3", max)
+		}
+
+		elapsed, _ := ElapsedFromContext(ctx)
+		if elapsed < last {
+			t.Errorf("ElapsedFromContext = %s, wanted at least %s", elapsed, last)
+		}
+		last = elapsed
+
+		return errors.New("test")
+	})
+
+	if len(got) != 3 || got[0] != 1 || got[1] != 2 || got[2] != 3 {
+		t.Errorf("AttemptFromContext over attempts = %v, wanted [1 2 3]", got)
+	}
+}
+
+func TestFirstAttempt(t *testing.T) {
+
+	if !FirstAttempt(context.Background()) || IsRetry(context.Background()) {
+		t.Error("context.Background() isn't treated as a first attempt")
+	}
+
+	tryer, err := New(nil, Options{
+		Retries:     2,
+		Base:        time.Millisecond * 1,
+		MaxInterval: time.Millisecond * 5,
+		MaxWait:     time.Second * 1,
+		Exponent:    2,
+	})
+	if err != nil {
+		t.Fatal("Failed to initialise Tryer while testing FirstAttempt:\n    ", err.Error())
+	}
+
+	var first, retry []bool
+	_, _ = tryer.TryContext(context.Background(), func(ctx context.Context) error {
+		first = append(first, FirstAttempt(ctx))
+		retry = append(retry, IsRetry(ctx))
+		return errors.New("test")
+	})
+
+	want := []bool{true, false, false}
+	for i := range want {
+		if first[i] != want[i] || retry[i] == want[i] {
+			t.Errorf("attempt %d: FirstAttempt = %t and IsRetry = %t, wanted %t and %t",
+				i+1, first[i], retry[i], want[i], !want[i])
+		}
+	}
+}
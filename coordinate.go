@@ -0,0 +1,62 @@
+package retry
+
+import (
+	"context"
+	"time"
+)
+
+/*
+	Coordinator lets instances of a service agree on when to retry, so that
+	after an outage of a shared dependency their retries are spread out
+	rather than arriving together. Jitter alone only spreads the retries of
+	one process; a Coordinator backed by shared state such as Redis or a
+	database spreads them across processes.
+
+	Coordinate receives the delay Try computed before the next attempt and
+	returns the delay to use instead. If it returns an error Try falls back
+	to the delay it computed.
+*/
+type Coordinator interface {
+	Coordinate(ctx context.Context, delay time.Duration) (time.Duration, error)
+}
+
+/*
+	Counter is a counter shared by every instance of a service, such as a
+	Redis key incremented with INCR. Next increments it and returns the new
+	value.
+*/
+type Counter interface {
+	Next(ctx context.Context) (int64, error)
+}
+
+/*
+	Spread returns a Coordinator that uses counter to hand out slots to
+	retries across all instances sharing it. Each retry waits its usual
+	delay plus its slot multiplied by width, where slots cycle from 0 to
+	slots-1. With slots of 10 and width of 100ms, retries from up to ten
+	instances land at least 100ms apart.
+*/
+func Spread(counter Counter, slots int, width time.Duration) Coordinator {
+	return &spread{counter: counter, slots: int64(slots), width: width}
+}
+
+type spread struct {
+	counter Counter
+	slots   int64
+	width   time.Duration
+}
+
+func (s *spread) Coordinate(ctx context.Context, delay time.Duration) (time.Duration, error) {
+	if s.slots <= 0 {
+		return delay, nil
+	}
+	n, err := s.counter.Next(ctx)
+	if err != nil {
+		return delay, err
+	}
+	slot := n % s.slots
+	if slot < 0 {
+		slot += s.slots
+	}
+	return delay + time.Duration(slot)*s.width, nil
+}
@@ -0,0 +1,51 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type counter struct {
+	n   atomic.Int64
+	err error
+}
+
+func (c *counter) Next(ctx context.Context) (int64, error) {
+	return c.n.Add(1), c.err
+}
+
+func TestSpread(t *testing.T) {
+
+	c := &counter{}
+	coord := Spread(c, 3, time.Millisecond*100)
+
+	var got []time.Duration
+	for i := 0; i < 4; i++ {
+		d, err := coord.Coordinate(context.Background(), time.Millisecond)
+		if err != nil {
+			t.Fatalf("Coordinate returned error %v, wanted nil", err)
+		}
+		got = append(got, d)
+	}
+
+	want := []time.Duration{
+		time.Millisecond * 101,
+		time.Millisecond * 201,
+		time.Millisecond * 1,
+		time.Millisecond * 101,
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Coordinate delays = %v, wanted %v", got, want)
+			break
+		}
+	}
+
+	c.err = errors.New("backend down")
+	if d, err := coord.Coordinate(context.Background(), time.Millisecond); err == nil || d != time.Millisecond {
+		t.Errorf("Coordinate with failing Counter = %s, %v, wanted 1ms and an error", d, err)
+	}
+}
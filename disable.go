@@ -0,0 +1,65 @@
+package retry
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+/*
+	ErrDisabled is returned from Try when the first attempt fails while
+	retries are disabled by Disable or Tryer.Disable.
+*/
+var ErrDisabled = errors.New("retries disabled")
+
+var disabled atomic.Bool
+
+/*
+	Disable turns off retries for every Tryer in the program until Enable
+	is called, so that each call to Try makes exactly one attempt. It is
+	a kill switch for operators to break retry feedback loops during a
+	severe incident, and is typically wired to an admin endpoint or a
+	signal. Calls already waiting between attempts stop with ErrDisabled
+	once their current wait ends and their next attempt fails.
+*/
+func Disable() {
+	disabled.Store(true)
+}
+
+/*
+	Enable turns retries back on after Disable. Tryers disabled with
+	Tryer.Disable stay disabled.
+*/
+func Enable() {
+	disabled.Store(false)
+}
+
+/*
+	Disabled reports whether retries are disabled for every Tryer by
+	Disable.
+*/
+func Disabled() bool {
+	return disabled.Load()
+}
+
+/*
+	Disable turns off retries for t alone, like the package level Disable.
+*/
+func (t *Tryer) Disable() {
+	t.disabled.Store(true)
+}
+
+/*
+	Enable turns retries for t back on after Tryer.Disable. Retries stay
+	off if they are disabled for every Tryer by Disable.
+*/
+func (t *Tryer) Enable() {
+	t.disabled.Store(false)
+}
+
+/*
+	Disabled reports whether retries are disabled for t, either by
+	Tryer.Disable or for every Tryer by Disable.
+*/
+func (t *Tryer) Disabled() bool {
+	return t.disabled.Load() || disabled.Load()
+}
@@ -0,0 +1,57 @@
+package retry
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDisable(t *testing.T) {
+
+	newTryer := func() *Tryer {
+		tryer, err := New(nil, Options{
+			Retries:     3,
+			Base:        time.Millisecond * 1,
+			MaxInterval: time.Millisecond * 5,
+			MaxWait:     time.Second * 1,
+			Exponent:    2,
+		})
+		if err != nil {
+			t.Fatal("Failed to initialise Tryer while testing Disable:\n    ", err.Error())
+		}
+		return tryer
+	}
+
+	try := func(tryer *Tryer) (int, error) {
+		calls := 0
+		_, err := tryer.Try(func() error {
+			calls++
+			return errors.New("test")
+		})
+		return calls, err
+	}
+
+	a, b := newTryer(), newTryer()
+
+	a.Disable()
+	if calls, err := try(a); calls != 1 || !errors.Is(err, ErrDisabled) {
+		t.Errorf("Try on disabled Tryer returned %v after %d calls, wanted %v after 1", err, calls, ErrDisabled)
+	}
+	if calls, err := try(b); calls != 4 || !errors.Is(err, ErrMaxRetries) {
+		t.Errorf("Try on other Tryer returned %v after %d calls, wanted %v after 4", err, calls, ErrMaxRetries)
+	}
+
+	Disable()
+	defer Enable()
+	a.Enable()
+	for _, tryer := range []*Tryer{a, b} {
+		if calls, err := try(tryer); calls != 1 || !errors.Is(err, ErrDisabled) || !tryer.Disabled() {
+			t.Errorf("Try while disabled globally returned %v after %d calls, wanted %v after 1", err, calls, ErrDisabled)
+		}
+	}
+
+	Enable()
+	if calls, err := try(a); calls != 4 || !errors.Is(err, ErrMaxRetries) {
+		t.Errorf("Try after Enable returned %v after %d calls, wanted %v after 4", err, calls, ErrMaxRetries)
+	}
+}
@@ -0,0 +1,97 @@
+package retry
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+	FromEnv returns Options read from environment variables named
+	RETRY_<PREFIX>_<FIELD>, where PREFIX is prefix in upper case. For a
+	prefix of "db" the variables are:
+
+		RETRY_DB_RETRIES          Options.Retries
+		RETRY_DB_MAX_ATTEMPTS     Options.MaxAttempts
+		RETRY_DB_BASE             Options.Base
+		RETRY_DB_MAX_INTERVAL     Options.MaxInterval
+		RETRY_DB_MIN_INTERVAL     Options.MinInterval
+		RETRY_DB_MAX_WAIT         Options.MaxWait
+		RETRY_DB_ATTEMPT_TIMEOUT  Options.AttemptTimeout
+		RETRY_DB_EXPONENT         Options.Exponent
+		RETRY_DB_MAX_EXPONENT     Options.MaxExponent
+		RETRY_DB_JITTER           Options.Jitter
+
+	If prefix is empty the variables are named RETRY_<FIELD>. Durations are
+	written as understood by time.ParseDuration. Unset variables leave the
+	corresponding field at its zero value. The Options are not validated
+	until they are passed to New.
+*/
+func FromEnv(prefix string) (Options, error) {
+
+	var o Options
+
+	name := "RETRY_"
+	if prefix != "" {
+		name += strings.ToUpper(prefix) + "_"
+	}
+
+	var err error
+	lookup := func(field string, parse func(string) error) {
+		v, ok := os.LookupEnv(name + field)
+		if !ok || err != nil {
+			return
+		}
+		if perr := parse(v); perr != nil {
+			err = fmt.Errorf("couldn't parse %s: %w", name+field, perr)
+		}
+	}
+
+	lookup("RETRIES", func(v string) (err error) {
+		o.Retries, err = strconv.Atoi(v)
+		return err
+	})
+	lookup("MAX_ATTEMPTS", func(v string) (err error) {
+		o.MaxAttempts, err = strconv.Atoi(v)
+		return err
+	})
+	lookup("BASE", func(v string) (err error) {
+		o.Base, err = time.ParseDuration(v)
+		return err
+	})
+	lookup("MAX_INTERVAL", func(v string) (err error) {
+		o.MaxInterval, err = time.ParseDuration(v)
+		return err
+	})
+	lookup("MIN_INTERVAL", func(v string) (err error) {
+		o.MinInterval, err = time.ParseDuration(v)
+		return err
+	})
+	lookup("MAX_WAIT", func(v string) (err error) {
+		o.MaxWait, err = time.ParseDuration(v)
+		return err
+	})
+	lookup("ATTEMPT_TIMEOUT", func(v string) (err error) {
+		o.AttemptTimeout, err = time.ParseDuration(v)
+		return err
+	})
+	lookup("EXPONENT", func(v string) (err error) {
+		o.Exponent, err = strconv.ParseFloat(v, 64)
+		return err
+	})
+	lookup("MAX_EXPONENT", func(v string) (err error) {
+		o.MaxExponent, err = strconv.ParseFloat(v, 64)
+		return err
+	})
+	lookup("JITTER", func(v string) (err error) {
+		o.Jitter, err = strconv.ParseFloat(v, 64)
+		return err
+	})
+
+	if err != nil {
+		return Options{}, err
+	}
+	return o, nil
+}
@@ -0,0 +1,53 @@
+package retry
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFromEnv(t *testing.T) {
+
+	t.Setenv("RETRY_DB_RETRIES", "3")
+	t.Setenv("RETRY_DB_BASE", "50ms")
+	t.Setenv("RETRY_DB_MAX_INTERVAL", "1s")
+	t.Setenv("RETRY_DB_MIN_INTERVAL", "10ms")
+	t.Setenv("RETRY_DB_MAX_WAIT", "2s")
+	t.Setenv("RETRY_DB_ATTEMPT_TIMEOUT", "500ms")
+	t.Setenv("RETRY_DB_EXPONENT", "2")
+	t.Setenv("RETRY_DB_MAX_EXPONENT", "3")
+	t.Setenv("RETRY_DB_JITTER", "0.5")
+
+	got, err := FromEnv("db")
+	want := Options{
+		Retries:        3,
+		Base:           time.Millisecond * 50,
+		MaxInterval:    time.Second * 1,
+		MinInterval:    time.Millisecond * 10,
+		MaxWait:        time.Second * 2,
+		AttemptTimeout: time.Millisecond * 500,
+		Exponent:       2,
+		MaxExponent:    3,
+		Jitter:         0.5,
+	}
+	if err != nil || !sameOptions(got, want) {
+		t.Errorf("FromEnv(%q)\n    return %+v, %v\n    wanted %+v, nil\n", "db", got, err, want)
+	}
+
+	// Unset variables are left at zero.
+	t.Setenv("RETRY_RETRIES", "2")
+	got, err = FromEnv("")
+	if err != nil || !sameOptions(got, Options{Retries: 2}) {
+		t.Errorf("FromEnv(%q)\n    return %+v, %v\n    wanted %+v, nil\n", "", got, err, Options{Retries: 2})
+	}
+
+	t.Setenv("RETRY_API_MAX_ATTEMPTS", "4")
+	got, err = FromEnv("api")
+	if err != nil || !sameOptions(got, Options{MaxAttempts: 4}) {
+		t.Errorf("FromEnv(%q)\n    return %+v, %v\n    wanted %+v, nil\n", "api", got, err, Options{MaxAttempts: 4})
+	}
+
+	t.Setenv("RETRY_DB_BASE", "soon")
+	if _, err := FromEnv("db"); err == nil {
+		t.Errorf("FromEnv(%q) with RETRY_DB_BASE=soon returned nil error, wanted error", "db")
+	}
+}
@@ -0,0 +1,153 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+/*
+	Error is the type of error returned by Try when it gives up on an
+	operation. It carries diagnostics about the failed operation so that
+	callers do not need to inspect the slice of errors returned alongside
+	it to find out what happened.
+
+	An Error matches the sentinel that describes why Try stopped, so
+	errors.Is(err, ErrTimeout) and similar checks continue to work. When
+	TryContext stops because its context is done, the Error matches
+	ctx.Err() instead:
+
+		_, err := t.Try(fn)
+		if errors.Is(err, retry.ErrTimeout) {
+			var re *retry.Error
+			errors.As(err, &re)
+			log.Printf("gave up after %s: %v", re.Elapsed(), re.LastError())
+		}
+*/
+type Error struct {
+	reason   error
+	cause    error
+	attempts int
+	elapsed  time.Duration
+	last     error
+	history  History
+}
+
+/*
+	Attempts returns the number of times the operation was called.
+*/
+func (e *Error) Attempts() int {
+	return e.attempts
+}
+
+/*
+	Elapsed returns the total time spent attempting the operation,
+	including the time spent waiting between attempts.
+*/
+func (e *Error) Elapsed() time.Duration {
+	return e.elapsed
+}
+
+/*
+	LastError returns the error from the final attempt of the operation,
+	or nil if the operation was never attempted.
+*/
+func (e *Error) LastError() error {
+	return e.last
+}
+
+/*
+	History returns the failed attempts of the operation made by the call
+	that returned e. For calls to Tryer.Resume it only includes attempts
+	made by that call.
+*/
+func (e *Error) History() History {
+	return e.history
+}
+
+func (e *Error) Error() string {
+	reason := e.reason.Error()
+	if e.cause != nil && e.cause != e.reason {
+		reason = fmt.Sprintf("%s (%v)", reason, e.cause)
+	}
+	if e.last == nil {
+		return fmt.Sprintf("%s after %d attempt(s) in %s",
+			reason, e.attempts, e.elapsed)
+	}
+	return fmt.Sprintf("%s after %d attempt(s) in %s: %v",
+		reason, e.attempts, e.elapsed, e.last)
+}
+
+/*
+	Timeout reports whether Try stopped because it ran out of time, either
+	because its context's deadline passed or because the next attempt
+	would have exceeded Options.MaxWait. It returns false when the context
+	was cancelled, as callers often want to retry a timed out operation
+	at a higher level but not a cancelled one. The distinction is also
+	available through errors.Is with context.DeadlineExceeded,
+	context.Canceled, and ErrTimeout.
+*/
+func (e *Error) Timeout() bool {
+	return e.reason == context.DeadlineExceeded || e.reason == ErrTimeout
+}
+
+/*
+	Is reports whether target is the sentinel error describing why
+	Try stopped, e.g. ErrMaxRetries or ErrTimeout.
+*/
+func (e *Error) Is(target error) bool {
+	return target == e.reason
+}
+
+/*
+	Unwrap returns the underlying cause of the error, if any. When
+	TryContext stops because its context is done this is the result
+	of context.Cause.
+*/
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+/*
+	OptionsError is the error returned by New and Tryer.Update when
+	Options are invalid. It identifies the offending fields so that tools
+	validating configuration can point at them.
+*/
+type OptionsError struct {
+	/*
+		Fields are the names of the fields of Options that break the
+		constraint, such as "Jitter", or "Retries" and "MaxAttempts" when
+		the constraint involves both.
+	*/
+	Fields []string
+
+	/*
+		Values are the values of Fields, in the same order.
+	*/
+	Values []any
+
+	/*
+		Constraint describes the rule that was broken, such as "between 0
+		and 1" or "at least Base".
+	*/
+	Constraint string
+
+	msg string
+}
+
+func (e *OptionsError) Error() string {
+	return e.msg
+}
+
+/*
+	optionsError returns an OptionsError for a single field, with a
+	message formatted from format and args.
+*/
+func optionsError(field string, value any, constraint string, format string, args ...any) *OptionsError {
+	return &OptionsError{
+		Fields:     []string{field},
+		Values:     []any{value},
+		Constraint: constraint,
+		msg:        fmt.Sprintf(format, args...),
+	}
+}
@@ -0,0 +1,147 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestError(t *testing.T) {
+
+	tryer, err := New(nil, Options{
+		Retries:     2,
+		Base:        time.Millisecond * 5,
+		MaxInterval: time.Millisecond * 20,
+		MaxWait:     time.Second * 1,
+		Exponent:    2,
+	})
+	if err != nil {
+		t.Fatal("Failed to initialise Tryer while testing type Error:\n    ", err.Error())
+	}
+
+	attempts := 0
+	last := errors.New("last")
+	_, err = tryer.Try(func() error {
+		attempts++
+		if attempts == 3 {
+			return last
+		}
+		return errors.New("test")
+	})
+
+	if !errors.Is(err, ErrMaxRetries) {
+		t.Fatalf("errors.Is(%v, ErrMaxRetries) = false, wanted true", err)
+	}
+
+	var re *Error
+	if !errors.As(err, &re) {
+		t.Fatalf("errors.As(%v, *Error) = false, wanted true", err)
+	}
+	if re.Attempts() != 3 {
+		t.Errorf("Error.Attempts() = %d, wanted 3", re.Attempts())
+	}
+	if re.LastError() != last {
+		t.Errorf("Error.LastError() = %v, wanted %v", re.LastError(), last)
+	}
+	if re.Elapsed() < time.Millisecond*5 {
+		t.Errorf("Error.Elapsed() = %s, wanted at least 5ms", re.Elapsed())
+	}
+}
+
+func TestErrorTimeout(t *testing.T) {
+
+	tryer, err := New(nil, Options{
+		Retries:     5,
+		Base:        time.Millisecond * 20,
+		MaxInterval: time.Millisecond * 20,
+		MaxWait:     time.Millisecond * 50,
+		Exponent:    1,
+	})
+	if err != nil {
+		t.Fatal("Failed to initialise Tryer while testing Error.Timeout:\n    ", err.Error())
+	}
+
+	deadline, cancelDeadline := context.WithTimeout(context.Background(), time.Millisecond*10)
+	defer cancelDeadline()
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	cases := []struct {
+		name     string
+		ctx      context.Context
+		wantErr  error
+		wantTime bool
+	}{
+		{"deadline", deadline, context.DeadlineExceeded, true},
+		{"cancelled", cancelled, context.Canceled, false},
+		{"max wait", context.Background(), ErrTimeout, true},
+	}
+
+	for _, c := range cases {
+
+		_, err := tryer.TryContext(c.ctx, func(context.Context) error {
+			return errors.New("test")
+		})
+
+		var re *Error
+		if !errors.As(err, &re) || !errors.Is(err, c.wantErr) || re.Timeout() != c.wantTime {
+			t.Errorf("TryContext stopped by %s returned %v, wanted an *Error matching %v with Timeout() %t",
+				c.name, err, c.wantErr, c.wantTime)
+		}
+	}
+}
+
+func TestOptionsError(t *testing.T) {
+
+	valid := Options{
+		Retries:     3,
+		Base:        time.Millisecond * 10,
+		MaxInterval: time.Millisecond * 50,
+		MaxWait:     time.Second * 1,
+		Exponent:    2,
+	}
+
+	cases := []struct {
+		modify         func(o *Options)
+		wantFields     []string
+		wantConstraint string
+	}{
+		{func(o *Options) { o.MaxAttempts = 4 }, []string{"Retries", "MaxAttempts"}, "only one set"},
+		{func(o *Options) { o.Window = time.Minute }, []string{"MaxRetriesPerWindow", "Window"}, "both or neither greater than 0"},
+		{func(o *Options) { o.Exponent = 0.5 }, []string{"Exponent"}, "at least 1"},
+		{func(o *Options) { o.Jitter = 2 }, []string{"Jitter"}, "between 0 and 1"},
+		{func(o *Options) { o.MinInterval = time.Second }, []string{"MinInterval"}, "between 0 and Base"},
+		{func(o *Options) { o.MaxInterval = time.Millisecond }, []string{"MaxInterval"}, "at least Base"},
+	}
+
+	for _, c := range cases {
+
+		o := valid
+		c.modify(&o)
+
+		_, err := New(nil, o)
+		var oe *OptionsError
+		if !errors.As(err, &oe) {
+			t.Errorf("New returned %v, wanted an *OptionsError for %v", err, c.wantFields)
+			continue
+		}
+
+		if len(oe.Fields) != len(c.wantFields) || len(oe.Values) != len(oe.Fields) || oe.Constraint != c.wantConstraint {
+			t.Errorf("New returned OptionsError with fields %v, values %v and constraint %q, wanted fields %v and constraint %q",
+				oe.Fields, oe.Values, oe.Constraint, c.wantFields, c.wantConstraint)
+			continue
+		}
+		for i := range oe.Fields {
+			if oe.Fields[i] != c.wantFields[i] {
+				t.Errorf("New returned OptionsError with fields %v, wanted %v", oe.Fields, c.wantFields)
+			}
+		}
+	}
+
+	_, err := New(nil, Options{Base: 1, MaxInterval: 1, Exponent: 1, Jitter: -1})
+	want := "expected a .Jitter value between 0 and 1, got -1.00"
+	if err == nil || err.Error() != want {
+		t.Errorf("New returned error %q, wanted %q", err, want)
+	}
+}
@@ -0,0 +1,55 @@
+package retry
+
+import "context"
+
+/*
+	Func returns a version of f that is retried by t, so an existing
+	function value can be made retrying without writing a closure at each
+	call site:
+
+		fetch := retry.Func(t, client.Fetch)
+		err := fetch(ctx, id)
+
+	The returned function calls t.TryContext and returns its overall
+	error, which is nil if f eventually succeeded.
+*/
+func Func[T any](t *Tryer, f func(context.Context, T) error) func(context.Context, T) error {
+	return func(ctx context.Context, arg T) error {
+		_, err := t.TryContext(ctx, func(ctx context.Context) error {
+			return f(ctx, arg)
+		})
+		return err
+	}
+}
+
+/*
+	Func2 is like Func for functions taking two arguments.
+*/
+func Func2[T, U any](t *Tryer, f func(context.Context, T, U) error) func(context.Context, T, U) error {
+	return func(ctx context.Context, arg1 T, arg2 U) error {
+		_, err := t.TryContext(ctx, func(ctx context.Context) error {
+			return f(ctx, arg1, arg2)
+		})
+		return err
+	}
+}
+
+/*
+	FuncResult is like Func for functions that also return a result. The
+	returned function returns the result of the successful attempt, or the
+	zero value of R if every attempt failed.
+*/
+func FuncResult[T, R any](t *Tryer, f func(context.Context, T) (R, error)) func(context.Context, T) (R, error) {
+	return func(ctx context.Context, arg T) (R, error) {
+		var result R
+		_, err := t.TryContext(ctx, func(ctx context.Context) error {
+			r, err := f(ctx, arg)
+			if err != nil {
+				return err
+			}
+			result = r
+			return nil
+		})
+		return result, err
+	}
+}
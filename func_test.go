@@ -0,0 +1,78 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestFunc(t *testing.T) {
+
+	tryer, err := New(nil, Options{
+		Retries:     3,
+		Base:        time.Millisecond * 1,
+		MaxInterval: time.Millisecond * 5,
+		MaxWait:     time.Second * 1,
+		Exponent:    2,
+	})
+	if err != nil {
+		t.Fatal("Failed to initialise Tryer while testing Func:\n    ", err.Error())
+	}
+
+	cases := []struct {
+		failures  int // attempts that fail before succeeding
+		wantErr   error
+		wantCalls int
+	}{
+		{0, nil, 1},
+		{2, nil, 3},
+		{10, ErrMaxRetries, 4},
+	}
+
+	for _, c := range cases {
+
+		calls := 0
+		parse := FuncResult(tryer, func(_ context.Context, s string) (int, error) {
+			calls++
+			if calls <= c.failures {
+				return 0, errors.New("test")
+			}
+			return strconv.Atoi(s)
+		})
+
+		n, err := parse(context.Background(), "42")
+		if !errors.Is(err, c.wantErr) || c.wantErr == nil && err != nil || calls != c.wantCalls {
+			t.Errorf("FuncResult failing %d times returned %v after %d calls, wanted %v after %d",
+				c.failures, err, calls, c.wantErr, c.wantCalls)
+		}
+		if want := 42; err == nil && n != want {
+			t.Errorf("FuncResult returned %d, wanted %d", n, want)
+		}
+		if err != nil && n != 0 {
+			t.Errorf("FuncResult returned %d after failing, wanted 0", n)
+		}
+	}
+
+	var got []string
+	send := Func2(tryer, func(_ context.Context, to, msg string) error {
+		got = append(got, to+":"+msg)
+		if len(got) < 2 {
+			return errors.New("test")
+		}
+		return nil
+	})
+	if err := send(context.Background(), "a", "b"); err != nil || len(got) != 2 || got[1] != "a:b" {
+		t.Errorf("Func2 returned %v with calls %q, wanted nil with calls [a:b a:b]", err, got)
+	}
+
+	calls := 0
+	fail := Func(tryer, func(context.Context, int) error {
+		calls++
+		return Permanent(errors.New("test"))
+	})
+	if err := fail(context.Background(), 1); !errors.Is(err, ErrCancelled) || calls != 1 {
+		t.Errorf("Func returned %v after %d calls, wanted %v after 1", err, calls, ErrCancelled)
+	}
+}
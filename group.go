@@ -0,0 +1,112 @@
+package retry
+
+import (
+	"context"
+	"slices"
+	"sync"
+)
+
+/*
+	Group deduplicates calls to TryContext that perform the same operation,
+	in the manner of golang.org/x/sync/singleflight. Concurrent callers
+	passing the same key share one retry loop and its result rather than
+	each retrying an expensive operation, such as refreshing one cache
+	entry, and multiplying the load on a dependency that is already
+	failing. A call arriving after the loop for its key has finished starts
+	a new one.
+
+	A Group is safe for concurrent use. Its zero value is not usable; use
+	NewGroup.
+*/
+type Group struct {
+	t       *Tryer
+	mu      sync.Mutex
+	flights map[string]*flight
+}
+
+/*
+	flight is a retry loop shared by the callers of Group.TryContext with
+	the same key.
+*/
+type flight struct {
+	done    chan struct{}
+	cancel  context.CancelFunc
+	callers int // Callers that have joined.
+	waiters int // Callers still waiting.
+	errs    []error
+	err     error
+}
+
+/*
+	NewGroup returns a Group that retries operations with t.
+*/
+func NewGroup(t *Tryer) *Group {
+	return &Group{t: t, flights: map[string]*flight{}}
+}
+
+/*
+	TryContext calls t.TryContext with fn unless a call with the same key
+	is already in progress, in which case it waits for that call and
+	returns its result. The shared result is true if the result was shared
+	with other callers. Each caller receives its own copy of errs.
+
+	The loop runs with the values of the first caller's context but isn't
+	cancelled when that caller's context is done, so long as other callers
+	are still waiting. Each caller stops waiting when its own ctx is done,
+	returning its error, and the loop is cancelled once every caller has
+	stopped waiting.
+*/
+func (g *Group) TryContext(ctx context.Context, key string, fn OperationCtx) (errs []error, err error, shared bool) {
+
+	g.mu.Lock()
+	f, ok := g.flights[key]
+	if ok {
+		f.callers++
+		f.waiters++
+		g.mu.Unlock()
+	} else {
+		fctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		f = &flight{done: make(chan struct{}), cancel: cancel, callers: 1, waiters: 1}
+		g.flights[key] = f
+		g.mu.Unlock()
+
+		go func() {
+			f.errs, f.err = g.t.TryContext(fctx, fn)
+			cancel()
+			g.mu.Lock()
+			g.forget(key, f)
+			g.mu.Unlock()
+			close(f.done)
+		}()
+	}
+
+	select {
+	case <-f.done:
+		g.mu.Lock()
+		shared = f.callers > 1
+		g.mu.Unlock()
+		return slices.Clone(f.errs), f.err, shared
+	case <-ctx.Done():
+		g.mu.Lock()
+		f.waiters--
+		if f.waiters == 0 {
+			// Later callers must start afresh rather
+			// than join a loop being cancelled.
+			g.forget(key, f)
+			f.cancel()
+		}
+		shared = f.callers > 1
+		g.mu.Unlock()
+		return nil, context.Cause(ctx), shared
+	}
+}
+
+/*
+	forget removes f from g if it is still the flight for key. g.mu must
+	be held.
+*/
+func (g *Group) forget(key string, f *flight) {
+	if g.flights[key] == f {
+		delete(g.flights, key)
+	}
+}
@@ -0,0 +1,116 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGroup(t *testing.T) {
+
+	tryer, err := New(nil, Options{
+		Retries:     3,
+		Base:        time.Millisecond * 5,
+		MaxInterval: time.Millisecond * 5,
+		MaxWait:     time.Second * 1,
+		Exponent:    1,
+	})
+	if err != nil {
+		t.Fatal("Failed to initialise Tryer while testing Group:\n    ", err.Error())
+	}
+	g := NewGroup(tryer)
+
+	// Every caller is started before the first attempt can
+	// finish, so all of them share the one retry loop.
+	const callers = 10
+	var calls atomic.Int32
+	release := make(chan struct{})
+	fn := func(ctx context.Context) error {
+		<-release
+		if calls.Add(1) < 3 {
+			return errors.New("test")
+		}
+		return nil
+	}
+
+	var wg sync.WaitGroup
+	var sharedCount atomic.Int32
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs, err, shared := g.TryContext(context.Background(), "key", fn)
+			if err != nil || len(errs) != 2 {
+				t.Errorf("Group.TryContext returned %v with %d errors, wanted nil with 2", err, len(errs))
+			}
+			if shared {
+				sharedCount.Add(1)
+			}
+		}()
+	}
+
+	for {
+		g.mu.Lock()
+		f := g.flights["key"]
+		joined := f != nil && f.callers == callers
+		g.mu.Unlock()
+		if joined {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+
+	if calls.Load() != 3 || sharedCount.Load() != callers {
+		t.Errorf("Group made %d calls and shared the result with %d callers, wanted 3 and %d",
+			calls.Load(), sharedCount.Load(), callers)
+	}
+
+	// A later call starts a new loop.
+	_, err, shared := g.TryContext(context.Background(), "key", func(ctx context.Context) error { return nil })
+	if err != nil || shared {
+		t.Errorf("Group.TryContext after the loop ended returned %v and shared = %t, wanted nil and false", err, shared)
+	}
+}
+
+func TestGroupCancel(t *testing.T) {
+
+	tryer, err := New(nil, Options{
+		Retries:     3,
+		Base:        time.Millisecond * 5,
+		MaxInterval: time.Millisecond * 5,
+		MaxWait:     time.Second * 1,
+		Exponent:    1,
+	})
+	if err != nil {
+		t.Fatal("Failed to initialise Tryer while testing Group:\n    ", err.Error())
+	}
+	g := NewGroup(tryer)
+
+	stopped := make(chan struct{})
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(time.Millisecond * 20)
+		cancel()
+	}()
+
+	// The only caller leaving cancels the shared loop.
+	_, err, _ = g.TryContext(ctx, "key", func(ctx context.Context) error {
+		<-ctx.Done()
+		close(stopped)
+		return ctx.Err()
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Group.TryContext returned %v after its context was cancelled, wanted %v", err, context.Canceled)
+	}
+
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Error("shared loop wasn't cancelled after its only caller left")
+	}
+}
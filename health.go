@@ -0,0 +1,20 @@
+package retry
+
+import (
+	"context"
+	"errors"
+)
+
+/*
+	ErrUnhealthy is returned from Try when it stops retrying because the
+	health check given by Options.Health reports the dependency is down.
+*/
+var ErrUnhealthy = errors.New("dependency is unhealthy")
+
+/*
+	HealthCheck reports whether a dependency is currently up. It should be
+	much cheaper than the operation being retried, for example reading the
+	state kept by a background prober or making a plain TCP connection
+	where the operation needs a full TLS handshake. See Options.Health.
+*/
+type HealthCheck = func(ctx context.Context) (up bool)
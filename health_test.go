@@ -0,0 +1,63 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestHealth(t *testing.T) {
+
+	cases := []struct {
+		name       string
+		downProbes int // probes reporting the dependency down before it recovers
+		wait       bool
+		maxWait    time.Duration
+		wantErr    error
+		wantCalls  int
+		wantProbes int
+	}{
+		{"healthy", 0, false, time.Second, nil, 2, 1},
+		{"fail fast", 1, false, time.Second, ErrUnhealthy, 1, 1},
+		{"wait for recovery", 3, true, time.Second, nil, 2, 4},
+		{"down past MaxWait", 1000, true, time.Millisecond * 50, ErrTimeout, 1, -1},
+	}
+
+	for _, c := range cases {
+
+		probes := 0
+		tryer, err := New(nil, Options{
+			Retries:     3,
+			Base:        time.Millisecond * 1,
+			MaxInterval: time.Millisecond * 5,
+			MaxWait:     c.maxWait,
+			Exponent:    2,
+			Health: func(ctx context.Context) bool {
+				probes++
+				return probes > c.downProbes
+			},
+			WaitForHealth: c.wait,
+		})
+		if err != nil {
+			t.Fatal("Failed to initialise Tryer while testing Health:\n    ", err.Error())
+		}
+
+		calls := 0
+		_, err = tryer.Try(func() error {
+			calls++
+			if calls == 1 {
+				return errors.New("test")
+			}
+			return nil
+		})
+
+		if !errors.Is(err, c.wantErr) || c.wantErr == nil && err != nil || calls != c.wantCalls {
+			t.Errorf("Try %s returned %v after %d calls, wanted %v after %d",
+				c.name, err, calls, c.wantErr, c.wantCalls)
+		}
+		if c.wantProbes >= 0 && probes != c.wantProbes {
+			t.Errorf("Try %s probed health %d times, wanted %d", c.name, probes, c.wantProbes)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package retry
+
+import (
+	"errors"
+	"time"
+)
+
+/*
+	Hint is advice from a server about how clients should back off, such
+	as an HTTP Retry-After header or gRPC retry pushback.
+*/
+type Hint struct {
+	/*
+		Delay is the least time the server asks clients to wait before
+		trying again. Try waits at least this long before the next attempt,
+		even if it is longer than Options.MaxInterval, or stops with
+		ErrTimeout if waiting would exceed Options.MaxWait.
+	*/
+	Delay time.Duration
+
+	/*
+		Shed reports that the server is shedding load, so that calls with
+		a priority of ShedPriority or lower shouldn't be retried at all.
+		Try stops such calls with ErrThrottled. See WithPriority.
+	*/
+	Shed         bool
+	ShedPriority Priority
+}
+
+/*
+	Hinter is implemented by errors that carry a Hint from the server that
+	returned them. Try looks for a Hinter in the error from each failed
+	attempt, including in the errors it wraps, and follows its Hint.
+	Clients for protocols with their own hints can implement Hinter on
+	their error types or wrap errors with WithHint.
+*/
+type Hinter interface {
+	Hint() Hint
+}
+
+type hintError struct {
+	err  error
+	hint Hint
+}
+
+func (e *hintError) Error() string {
+	return e.err.Error()
+}
+
+func (e *hintError) Unwrap() error {
+	return e.err
+}
+
+func (e *hintError) Hint() Hint {
+	return e.hint
+}
+
+/*
+	WithHint wraps err so that it implements Hinter, returning h. WithHint
+	returns nil if err is nil.
+*/
+func WithHint(err error, h Hint) error {
+	if err == nil {
+		return nil
+	}
+	return &hintError{err, h}
+}
+
+/*
+	hintFrom returns the Hint of the first Hinter in err's chain.
+*/
+func hintFrom(err error) (Hint, bool) {
+	var h Hinter
+	if errors.As(err, &h) {
+		return h.Hint(), true
+	}
+	return Hint{}, false
+}
@@ -0,0 +1,63 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestHint(t *testing.T) {
+
+	cases := []struct {
+		name      string
+		hint      Hint
+		priority  Priority
+		wantErr   error
+		wantCalls int
+		wantDelay time.Duration // least delay before the retry
+	}{
+		{"delay", Hint{Delay: time.Millisecond * 40}, PriorityNormal, nil, 2, time.Millisecond * 40},
+		{"delay past MaxWait", Hint{Delay: time.Minute}, PriorityNormal, ErrTimeout, 1, 0},
+		{"longest delay", Hint{Delay: math.MaxInt64}, PriorityNormal, ErrTimeout, 1, 0},
+		{"shed", Hint{Shed: true}, PriorityNormal, ErrThrottled, 1, 0},
+		{"shed low", Hint{Shed: true, ShedPriority: PriorityLow}, PriorityNormal, nil, 2, 0},
+		{"shed low and low", Hint{Shed: true, ShedPriority: PriorityLow}, PriorityLow, ErrThrottled, 1, 0},
+	}
+
+	for _, c := range cases {
+
+		tryer, err := New(nil, Options{
+			Retries:     3,
+			Base:        time.Millisecond * 1,
+			MaxInterval: time.Millisecond * 5,
+			MaxWait:     time.Second * 1,
+			Exponent:    2,
+		})
+		if err != nil {
+			t.Fatal("Failed to initialise Tryer while testing Hint:\n    ", err.Error())
+		}
+
+		calls := 0
+		var first time.Time
+		ctx := WithPriority(context.Background(), c.priority)
+		_, err = tryer.TryContext(ctx, func(ctx context.Context) error {
+			calls++
+			if calls == 1 {
+				first = time.Now()
+				return fmt.Errorf("request: %w", WithHint(errors.New("test"), c.hint))
+			}
+			if d := time.Since(first); d < c.wantDelay {
+				t.Errorf("Try %s retried after %s, wanted at least %s", c.name, d, c.wantDelay)
+			}
+			return nil
+		})
+
+		if !errors.Is(err, c.wantErr) || c.wantErr == nil && err != nil || calls != c.wantCalls {
+			t.Errorf("Try %s returned %v after %d calls, wanted %v after %d",
+				c.name, err, calls, c.wantErr, c.wantCalls)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package retry
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+/*
+	Attempt describes a failed attempt of an operation.
+*/
+type Attempt struct {
+	/*
+		N is the number of the attempt, counting from 1.
+	*/
+	N int
+
+	/*
+		Start is when the attempt began.
+	*/
+	Start time.Time
+
+	/*
+		Duration is how long the attempt took.
+	*/
+	Duration time.Duration
+
+	/*
+		Delay is how long Try actually waited after the attempt before
+		making the next one. It is 0 if Try hasn't waited yet or didn't
+		make another attempt.
+	*/
+	Delay time.Duration
+
+	/*
+		Err is the error the attempt returned.
+	*/
+	Err error
+}
+
+/*
+	History is the failed attempts of a call to Try in the order they were
+	made. It is reported to Options.OnRetry in State.History and returned
+	by Error.History.
+*/
+type History []Attempt
+
+/*
+	attemptJSON is the JSON representation of an Attempt.
+*/
+type attemptJSON struct {
+	N        int       `json:"attempt"`
+	Start    time.Time `json:"start"`
+	Duration duration  `json:"duration"`
+	Delay    duration  `json:"delay"`
+	Err      string    `json:"error"`
+}
+
+func (a Attempt) toJSON() attemptJSON {
+	j := attemptJSON{
+		N:        a.N,
+		Start:    a.Start,
+		Duration: duration(a.Duration),
+		Delay:    duration(a.Delay),
+	}
+	if a.Err != nil {
+		j.Err = a.Err.Error()
+	}
+	return j
+}
+
+/*
+	MarshalJSON encodes a as a JSON object such as:
+
+		{
+			"attempt":  1,
+			"start":    "2024-05-01T12:00:00.123456789Z",
+			"duration": "20.5ms",
+			"delay":    "50ms",
+			"error":    "connection refused"
+		}
+*/
+func (a Attempt) MarshalJSON() ([]byte, error) {
+	return json.Marshal(a.toJSON())
+}
+
+/*
+	MarshalJSON encodes h as a JSON array of attempts in the format of
+	Attempt.MarshalJSON, so the history of a failed call can be logged or
+	returned to an API caller as structured data. An empty History is
+	encoded as an empty array rather than null.
+*/
+func (h History) MarshalJSON() ([]byte, error) {
+	j := make([]attemptJSON, len(h))
+	for i, a := range h {
+		j[i] = a.toJSON()
+	}
+	return json.Marshal(j)
+}
+
+/*
+	repeats returns how many of the errors at the end of errs are the same
+	as the last one. See Options.MaxRepeats.
+*/
+func repeats(errs []error) int {
+	if len(errs) == 0 {
+		return 0
+	}
+	last := errs[len(errs)-1]
+	n := 1
+	for i := len(errs) - 2; i >= 0; i-- {
+		if !errors.Is(last, errs[i]) && !errors.Is(errs[i], last) && last.Error() != errs[i].Error() {
+			break
+		}
+		n++
+	}
+	return n
+}
@@ -0,0 +1,143 @@
+package retry
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestHistory(t *testing.T) {
+
+	var lens []int
+	var delays []time.Duration
+	var kept []State
+	tryer, err := New(nil, Options{
+		Retries:     3,
+		Base:        time.Millisecond * 5,
+		MaxInterval: time.Millisecond * 5,
+		MaxWait:     time.Second * 1,
+		Exponent:    1,
+		OnRetry: func(s State, err error) {
+			lens = append(lens, len(s.History))
+			if last := s.History[len(s.History)-1]; last.Err != err || last.N != s.Attempts {
+				t.Errorf("Last attempt in History is %+v, wanted attempt %d with error %v", last, s.Attempts, err)
+			}
+			if len(s.History) > 1 {
+				delays = append(delays, s.History[len(s.History)-2].Delay)
+			}
+			kept = append(kept, s)
+		},
+	})
+	if err != nil {
+		t.Fatal("Failed to initialise Tryer while testing History:\n    ", err.Error())
+	}
+
+	attempts := 0
+	_, err = tryer.Try(func() error {
+		attempts++
+		return fmt.Errorf("attempt %d", attempts)
+	})
+
+	if fmt.Sprint(lens) != "[1 2 3]" {
+		t.Errorf("OnRetry saw History lengths %v, wanted [1 2 3]", lens)
+	}
+	for _, d := range delays {
+		if d < time.Millisecond*5 {
+			t.Errorf("History recorded a delay of %s, wanted at least 5ms", d)
+		}
+	}
+
+	var re *Error
+	if !errors.As(err, &re) {
+		t.Fatalf("Try returned %v, wanted an *Error", err)
+	}
+	h := re.History()
+	if len(h) != 4 {
+		t.Fatalf("Error.History() has %d attempts, wanted 4", len(h))
+	}
+	for i, a := range h {
+		if a.N != i+1 || a.Err.Error() != fmt.Sprintf("attempt %d", i+1) || a.Start.IsZero() {
+			t.Errorf("Error.History()[%d] = %+v, wanted attempt %d", i, a, i+1)
+		}
+	}
+	if h[3].Delay != 0 {
+		t.Errorf("Final attempt has a delay of %s, wanted 0", h[3].Delay)
+	}
+
+	// A State kept by OnRetry isn't changed by Try afterwards.
+	for _, s := range kept {
+		if d := s.History[len(s.History)-1].Delay; d != 0 {
+			t.Errorf("History kept from OnRetry after attempt %d later had a delay of %s, wanted 0", s.Attempts, d)
+		}
+	}
+}
+
+func TestMaxRepeats(t *testing.T) {
+
+	errA := errors.New("a")
+
+	cases := []struct {
+		results   []error // returned by successive attempts
+		wantErr   error
+		wantCalls int
+	}{
+		{[]error{errA, errA, errA}, ErrRepeatedFailure, 3},
+		{[]error{errA, errors.New("b"), errA, fmt.Errorf("wrapped: %w", errA), errA}, ErrRepeatedFailure, 5},
+		{[]error{errA, errors.New("a"), errors.New("a")}, ErrRepeatedFailure, 3},
+		{[]error{errA, errA, errors.New("b"), errA, errA, errors.New("b")}, ErrMaxRetries, 6},
+	}
+
+	for _, c := range cases {
+
+		tryer, err := New(nil, Options{
+			Retries:     5,
+			Base:        time.Millisecond * 1,
+			MaxInterval: time.Millisecond * 5,
+			MaxWait:     time.Second * 1,
+			Exponent:    2,
+			MaxRepeats:  3,
+		})
+		if err != nil {
+			t.Fatal("Failed to initialise Tryer while testing MaxRepeats:\n    ", err.Error())
+		}
+
+		calls := 0
+		_, err = tryer.Try(func() error {
+			calls++
+			return c.results[calls-1]
+		})
+		if !errors.Is(err, c.wantErr) || calls != c.wantCalls {
+			t.Errorf("Try with errors %v returned %v after %d calls, wanted %v after %d",
+				c.results, err, calls, c.wantErr, c.wantCalls)
+		}
+	}
+}
+
+func TestHistoryMarshalJSON(t *testing.T) {
+
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	h := History{
+		{N: 1, Start: start, Duration: time.Millisecond * 20, Delay: time.Millisecond * 50, Err: errors.New("refused")},
+		{N: 2, Start: start.Add(time.Millisecond * 70), Duration: time.Millisecond * 10, Err: errors.New("reset")},
+	}
+
+	cases := []struct {
+		h    History
+		want string
+	}{
+		{nil, `[]`},
+		{h, `[` +
+			`{"attempt":1,"start":"2024-05-01T12:00:00Z","duration":"20ms","delay":"50ms","error":"refused"},` +
+			`{"attempt":2,"start":"2024-05-01T12:00:00.07Z","duration":"10ms","delay":"0s","error":"reset"}` +
+			`]`},
+	}
+
+	for _, c := range cases {
+		b, err := json.Marshal(c.h)
+		if err != nil || string(b) != c.want {
+			t.Errorf("json.Marshal(History)\n    return %s, %v\n    wanted %s, nil\n", b, err, c.want)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package retry
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+type idempotencyKey struct{}
+
+func withIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKey{}, key)
+}
+
+/*
+	IdempotencyKeyFromContext returns the idempotency key attached to a
+	context passed to an OperationCtx by TryContext. The key is the same
+	for every attempt within one call to TryContext. The ok result is false
+	if Options.IdempotencyKey was nil or ctx did not come from TryContext.
+*/
+func IdempotencyKeyFromContext(ctx context.Context) (key string, ok bool) {
+	key, ok = ctx.Value(idempotencyKey{}).(string)
+	return key, ok
+}
+
+/*
+	NewIdempotencyKey returns a random 128 bit key encoded as hexadecimal.
+	It is suitable for use as Options.IdempotencyKey.
+*/
+func NewIdempotencyKey() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic("retry: couldn't read random bytes: " + err.Error())
+	}
+	return hex.EncodeToString(b[:])
+}
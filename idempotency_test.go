@@ -0,0 +1,54 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestIdempotencyKey(t *testing.T) {
+
+	type header struct{}
+
+	tryer, err := New(nil, Options{
+		Retries:        2,
+		Base:           time.Millisecond * 5,
+		MaxInterval:    time.Millisecond * 20,
+		MaxWait:        time.Second * 1,
+		Exponent:       2,
+		IdempotencyKey: NewIdempotencyKey,
+		BeforeAttempt: func(ctx context.Context) context.Context {
+			key, _ := IdempotencyKeyFromContext(ctx)
+			return context.WithValue(ctx, header{}, key)
+		},
+	})
+	if err != nil {
+		t.Fatal("Failed to initialise Tryer while testing IdempotencyKey:\n    ", err.Error())
+	}
+
+	try := func() []string {
+		var keys []string
+		_, _ = tryer.TryContext(context.Background(), func(ctx context.Context) error {
+			key, ok := ctx.Value(header{}).(string)
+			if !ok || key == "" {
+				t.Fatal("BeforeAttempt did not attach the idempotency key")
+			}
+			keys = append(keys, key)
+			return errors.New("test")
+		})
+		return keys
+	}
+
+	first := try()
+	for _, key := range first {
+		if key != first[0] {
+			t.Errorf("idempotency keys within one call = %v, wanted all equal", first)
+			break
+		}
+	}
+
+	if second := try(); second[0] == first[0] {
+		t.Errorf("idempotency keys of separate calls are both %q, wanted them to differ", first[0])
+	}
+}
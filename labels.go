@@ -0,0 +1,30 @@
+package retry
+
+import (
+	"context"
+	"runtime/pprof"
+	"strconv"
+)
+
+/*
+	ProfileLabels returns a Middleware that runs each attempt with the
+	pprof labels retry_operation, set to name, and retry_attempt, set to
+	the attempt number. CPU profiles taken while a dependency is flaky then
+	attribute time to the operations being retried and show how much of it
+	goes on retries rather than first attempts.
+
+	The labels are also set on goroutines the operation starts with the
+	context it receives, as described for pprof.Do.
+*/
+func ProfileLabels(name string) Middleware {
+	return func(next OperationCtx) OperationCtx {
+		return func(ctx context.Context) (err error) {
+			n, _ := AttemptFromContext(ctx)
+			labels := pprof.Labels("retry_operation", name, "retry_attempt", strconv.Itoa(n))
+			pprof.Do(ctx, labels, func(ctx context.Context) {
+				err = next(ctx)
+			})
+			return err
+		}
+	}
+}
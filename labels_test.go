@@ -0,0 +1,40 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/pprof"
+	"testing"
+	"time"
+)
+
+func TestProfileLabels(t *testing.T) {
+
+	tryer, err := New(nil, Options{
+		Retries:     2,
+		Base:        time.Millisecond * 1,
+		MaxInterval: time.Millisecond * 5,
+		MaxWait:     time.Second * 1,
+		Exponent:    2,
+		Middleware:  []Middleware{ProfileLabels("fetch")},
+	})
+	if err != nil {
+		t.Fatal("Failed to initialise Tryer while testing ProfileLabels:\n    ", err.Error())
+	}
+
+	var got []string
+	tryer.TryContext(context.Background(), func(ctx context.Context) error {
+		op, _ := pprof.Label(ctx, "retry_operation")
+		n, _ := pprof.Label(ctx, "retry_attempt")
+		got = append(got, op+"#"+n)
+		if len(got) < 2 {
+			return errors.New("test")
+		}
+		return nil
+	})
+
+	if want := "[fetch#1 fetch#2]"; fmt.Sprint(got) != want {
+		t.Errorf("Attempts had labels %v, wanted %s", got, want)
+	}
+}
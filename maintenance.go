@@ -0,0 +1,45 @@
+package retry
+
+import (
+	"errors"
+	"time"
+)
+
+/*
+	ErrMaintenance is returned from Try when it stops retrying because a
+	maintenance window given by Options.Maintenance is in progress.
+*/
+var ErrMaintenance = errors.New("dependency is down for maintenance")
+
+/*
+	Maintenance reports whether a dependency is in planned downtime at the
+	given time. If it is, end is when the downtime finishes; otherwise end
+	is the zero Time. See Options.Maintenance.
+*/
+type Maintenance = func(now time.Time) (end time.Time)
+
+/*
+	MaintenanceWindow is a period of planned downtime, from Start up to
+	but not including End.
+*/
+type MaintenanceWindow struct {
+	Start time.Time
+	End   time.Time
+}
+
+/*
+	Windows returns a Maintenance for a fixed list of windows, for example
+	read from a configuration file. Where windows overlap, the end of the
+	latest finishing window that contains now is reported.
+*/
+func Windows(windows ...MaintenanceWindow) Maintenance {
+	return func(now time.Time) time.Time {
+		var end time.Time
+		for _, w := range windows {
+			if !now.Before(w.Start) && now.Before(w.End) && w.End.After(end) {
+				end = w.End
+			}
+		}
+		return end
+	}
+}
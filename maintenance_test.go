@@ -0,0 +1,90 @@
+package retry
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWindows(t *testing.T) {
+
+	t0 := time.Date(2024, 5, 1, 2, 0, 0, 0, time.UTC)
+	m := Windows(
+		MaintenanceWindow{t0, t0.Add(time.Hour)},
+		MaintenanceWindow{t0.Add(time.Minute * 30), t0.Add(time.Hour * 2)},
+	)
+
+	cases := []struct {
+		now  time.Time
+		want time.Time
+	}{
+		{t0.Add(-time.Second), time.Time{}},
+		{t0, t0.Add(time.Hour)},
+		{t0.Add(time.Minute * 45), t0.Add(time.Hour * 2)},
+		{t0.Add(time.Hour * 2), time.Time{}},
+	}
+
+	for _, c := range cases {
+		if got := m(c.now); !got.Equal(c.want) {
+			t.Errorf("Windows(...)(%s) = %s, wanted %s", c.now, got, c.want)
+		}
+	}
+}
+
+func TestMaintenance(t *testing.T) {
+
+	now := time.Now()
+
+	cases := []struct {
+		window    MaintenanceWindow
+		waitOut   bool
+		wantErr   error
+		wantCalls int
+	}{
+		// Outside the window failures are retried as usual.
+		{MaintenanceWindow{now.Add(-time.Hour), now.Add(-time.Minute)}, false, nil, 2},
+
+		// Inside the window Try stops after the first attempt.
+		{MaintenanceWindow{now.Add(-time.Minute), now.Add(time.Minute)}, false, ErrMaintenance, 1},
+
+		// Waiting out a short window retries once it ends.
+		{MaintenanceWindow{now.Add(-time.Minute), now.Add(time.Millisecond * 50)}, true, nil, 2},
+
+		// A window ending after MaxWait is a timeout.
+		{MaintenanceWindow{now.Add(-time.Minute), now.Add(time.Minute)}, true, ErrTimeout, 1},
+	}
+
+	for _, c := range cases {
+
+		tryer, err := New(nil, Options{
+			Retries:            3,
+			Base:               time.Millisecond * 1,
+			MaxInterval:        time.Millisecond * 5,
+			MaxWait:            time.Second * 1,
+			Exponent:           2,
+			Maintenance:        Windows(c.window),
+			WaitOutMaintenance: c.waitOut,
+		})
+		if err != nil {
+			t.Fatal("Failed to initialise Tryer while testing Maintenance:\n    ", err.Error())
+		}
+
+		calls := 0
+		start := time.Now()
+		_, err = tryer.Try(func() error {
+			calls++
+			if calls == 1 {
+				return errors.New("test")
+			}
+			return nil
+		})
+
+		if !errors.Is(err, c.wantErr) || c.wantErr == nil && err != nil || calls != c.wantCalls {
+			t.Errorf("Try with window %v returned %v after %d calls, wanted %v after %d",
+				c.window, err, calls, c.wantErr, c.wantCalls)
+		}
+		if c.waitOut && c.wantErr == nil && time.Now().Before(c.window.End) {
+			t.Errorf("Try returned %s after starting, before the window ended", time.Since(start))
+		}
+	}
+}
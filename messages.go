@@ -0,0 +1,50 @@
+package retry
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+/*
+	Messages returns a Retry that retries errors whose message contains
+	any of substrings or matches any of the regular expressions in
+	patterns. It is meant for third party libraries that don't export
+	error types or values to test for. The message is that of the whole
+	error, including any context added by wrapping.
+
+	Messages returns an error if a pattern doesn't compile, or if there are
+	no substrings or patterns, so mistakes show up when the Tryer is set
+	up rather than as errors that are never retried.
+*/
+func Messages(substrings, patterns []string) (Retry, error) {
+
+	if len(substrings) == 0 && len(patterns) == 0 {
+		return nil, errors.New("expected at least one substring or pattern to match")
+	}
+
+	res := make([]*regexp.Regexp, len(patterns))
+	for i, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't compile pattern %q: %w", p, err)
+		}
+		res[i] = re
+	}
+
+	return func(err error) bool {
+		msg := err.Error()
+		for _, s := range substrings {
+			if strings.Contains(msg, s) {
+				return true
+			}
+		}
+		for _, re := range res {
+			if re.MatchString(msg) {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
@@ -0,0 +1,42 @@
+package retry
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestMessages(t *testing.T) {
+
+	if _, err := Messages(nil, nil); err == nil {
+		t.Errorf("Messages(nil, nil) returned nil error, wanted error")
+	}
+	if _, err := Messages(nil, []string{"("}); err == nil {
+		t.Errorf("Messages with invalid pattern returned nil error, wanted error")
+	}
+
+	retry, err := Messages(
+		[]string{"connection reset"},
+		[]string{`^status 5\d\d`},
+	)
+	if err != nil {
+		t.Fatal("Failed to initialise Messages:\n    ", err.Error())
+	}
+
+	cases := []struct {
+		err  error
+		want bool
+	}{
+		{errors.New("read: connection reset by peer"), true},
+		{fmt.Errorf("fetch: %w", errors.New("connection reset")), true},
+		{errors.New("status 503: unavailable"), true},
+		{errors.New("status 404: not found"), false},
+		{errors.New("got status 503"), false},
+	}
+
+	for _, c := range cases {
+		if got := retry(c.err); got != c.want {
+			t.Errorf("Messages retry(%q) = %t, wanted %t", c.err, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package retry
+
+import "context"
+
+type onceKey struct{ _ byte }
+
+/*
+	OnceAcrossAttempts wraps fn, typically a side effect such as sending
+	a notification, so that once it has succeeded it isn't called again by
+	later attempts of the same call to TryContext, even though the
+	operation calling it is retried. This suits flows that trigger
+	something and then poll for its result:
+
+		notify := retry.OnceAcrossAttempts(sendNotification)
+		_, err := t.TryContext(ctx, func(ctx context.Context) error {
+			if err := notify(ctx); err != nil {
+				return err
+			}
+			return poll(ctx)
+		})
+
+	Later attempts get nil without calling fn. If fn fails it is called
+	again by the next attempt, so it must be safe to repeat after a
+	failure. Each call to TryContext is tracked separately, so the
+	returned function can be shared by many calls, but Resume starts
+	afresh. Outside TryContext the returned function always calls fn.
+*/
+func OnceAcrossAttempts(fn func(ctx context.Context) error) func(ctx context.Context) error {
+
+	key := &onceKey{}
+
+	return func(ctx context.Context) error {
+
+		a, ok := ctx.Value(attemptKey{}).(*attemptCtx)
+		if !ok {
+			return fn(ctx)
+		}
+		call := a.call
+
+		// The lock is held while fn runs so that attempts
+		// overlapping through a Middleware can't both call it.
+		call.mu.Lock()
+		defer call.mu.Unlock()
+		if call.done[key] {
+			return nil
+		}
+		if err := fn(ctx); err != nil {
+			return err
+		}
+		if call.done == nil {
+			call.done = make(map[*onceKey]bool)
+		}
+		call.done[key] = true
+		return nil
+	}
+}
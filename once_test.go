@@ -0,0 +1,79 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestOnceAcrossAttempts(t *testing.T) {
+
+	tryer, err := New(nil, Options{
+		Retries:     3,
+		Base:        time.Millisecond * 1,
+		MaxInterval: time.Millisecond * 5,
+		MaxWait:     time.Second * 1,
+		Exponent:    2,
+	})
+	if err != nil {
+		t.Fatal("Failed to initialise Tryer while testing OnceAcrossAttempts:\n    ", err.Error())
+	}
+
+	cases := []struct {
+		name        string
+		notifyFails int // failed calls to the side effect before it succeeds
+		pollFails   int // failed polls after notifying
+		wantNotify  int
+		wantPolls   int
+	}{
+		{"no failures", 0, 0, 1, 1},
+		{"poll fails", 0, 2, 1, 3},
+		{"notify fails", 1, 1, 2, 2},
+	}
+
+	notifications := 0
+	notifyFails := 0
+	notify := OnceAcrossAttempts(func(ctx context.Context) error {
+		notifications++
+		if notifyFails > 0 {
+			notifyFails--
+			return errors.New("test")
+		}
+		return nil
+	})
+
+	// The same wrapped function is shared by every call,
+	// so each call must be tracked separately.
+	for _, c := range cases {
+
+		notifications = 0
+		notifyFails = c.notifyFails
+		polls := 0
+		pollFails := c.pollFails
+
+		_, err := tryer.TryContext(context.Background(), func(ctx context.Context) error {
+			if err := notify(ctx); err != nil {
+				return err
+			}
+			polls++
+			if pollFails > 0 {
+				pollFails--
+				return errors.New("test")
+			}
+			return nil
+		})
+
+		if err != nil || notifications != c.wantNotify || polls != c.wantPolls {
+			t.Errorf("TryContext %s returned %v after %d notifications and %d polls, wanted nil after %d and %d",
+				c.name, err, notifications, polls, c.wantNotify, c.wantPolls)
+		}
+	}
+
+	notifications = 0
+	_ = notify(context.Background())
+	_ = notify(context.Background())
+	if notifications != 2 {
+		t.Errorf("OnceAcrossAttempts outside TryContext called fn %d times, wanted 2", notifications)
+	}
+}
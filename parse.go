@@ -0,0 +1,185 @@
+package retry
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+	Parse returns the Options described by a compact policy string, for use
+	in command line flags and environment variables. For example:
+
+		exponential(base=50ms, max=1s, budget=2s, retries=3, jitter=0.5)
+
+	The policy is either exponential, where delays grow by exponent (2 if
+	not given), or constant, where every delay is base. The parameters are:
+
+		base        Options.Base
+		max         Options.MaxInterval (defaults to base for constant)
+		min         Options.MinInterval
+		budget      Options.MaxWait
+		timeout     Options.AttemptTimeout
+		retries     Options.Retries
+		attempts    Options.MaxAttempts
+		exponent    Options.Exponent (exponential only)
+		maxexponent Options.MaxExponent (exponential only)
+		jitter      Options.Jitter
+
+	Durations are written as understood by time.ParseDuration. The Options
+	are not validated until they are passed to New.
+*/
+func Parse(policy string) (Options, error) {
+	return parse(Options{}, policy)
+}
+
+/*
+	parse returns o with the fields named by policy set. See Parse.
+*/
+func parse(o Options, policy string) (Options, error) {
+
+	s := strings.TrimSpace(policy)
+	open := strings.IndexByte(s, '(')
+	if open < 0 || !strings.HasSuffix(s, ")") {
+		return o, fmt.Errorf("expected a policy such as exponential(base=50ms, ...), got %q", policy)
+	}
+
+	name := strings.TrimSpace(s[:open])
+	switch name {
+	case "exponential":
+		o.Exponent = 2
+	case "constant":
+		o.Exponent = 1
+		o.MaxExponent = 0
+	default:
+		return o, fmt.Errorf("expected policy exponential or constant, got %q", name)
+	}
+
+	var args []string
+	if a := strings.TrimSpace(s[open+1 : len(s)-1]); a != "" {
+		args = strings.Split(a, ",")
+	}
+
+	seen := map[string]bool{}
+	for _, arg := range args {
+
+		k, v, ok := strings.Cut(arg, "=")
+		k, v = strings.TrimSpace(k), strings.TrimSpace(v)
+		if !ok || k == "" || v == "" {
+			return o, fmt.Errorf("expected parameter of the form name=value, got %q", strings.TrimSpace(arg))
+		}
+		if seen[k] {
+			return o, fmt.Errorf("parameter %q given more than once", k)
+		}
+		seen[k] = true
+
+		var err error
+		switch k {
+		case "base":
+			o.Base, err = time.ParseDuration(v)
+		case "max":
+			o.MaxInterval, err = time.ParseDuration(v)
+		case "min":
+			o.MinInterval, err = time.ParseDuration(v)
+		case "budget":
+			o.MaxWait, err = time.ParseDuration(v)
+		case "timeout":
+			o.AttemptTimeout, err = time.ParseDuration(v)
+		case "retries":
+			o.Retries, err = strconv.Atoi(v)
+		case "attempts":
+			o.MaxAttempts, err = strconv.Atoi(v)
+		case "jitter":
+			o.Jitter, err = strconv.ParseFloat(v, 64)
+		case "exponent":
+			if name == "constant" {
+				return o, fmt.Errorf("parameter exponent isn't valid for policy constant")
+			}
+			o.Exponent, err = strconv.ParseFloat(v, 64)
+		case "maxexponent":
+			if name == "constant" {
+				return o, fmt.Errorf("parameter maxexponent isn't valid for policy constant")
+			}
+			o.MaxExponent, err = strconv.ParseFloat(v, 64)
+		default:
+			return o, fmt.Errorf("unknown parameter %q", k)
+		}
+		if err != nil {
+			return o, fmt.Errorf("couldn't parse parameter %q: %w", k, err)
+		}
+	}
+
+	if name == "constant" && !seen["max"] {
+		o.MaxInterval = o.Base
+	}
+
+	// Only one limit on attempts may be set.
+	if seen["retries"] && !seen["attempts"] {
+		o.MaxAttempts = 0
+	}
+	if seen["attempts"] && !seen["retries"] {
+		o.Retries = 0
+	}
+
+	return o, nil
+}
+
+/*
+	String returns o as a policy string in the format read by Parse, for
+	example when logging the retry policies a service is using. The limit
+	on attempts is written as attempts rather than retries when MaxAttempts
+	is set, and min, timeout and maxexponent only when they aren't 0.
+	Hooks and the other funcs, interfaces and pointers are omitted, as are
+	these fields, which a policy string can't describe: SkipFirstJitter,
+	CheckAttemptTimeouts, UncountedTimeouts, Seed, MaxRepeats,
+	WaitOutMaintenance, WaitForHealth, MaxRetriesPerWindow, Window and
+	RecentOutcomes.
+*/
+func (o Options) String() string {
+
+	name := "exponential"
+	exponent := ", exponent=" + strconv.FormatFloat(o.Exponent, 'g', -1, 64)
+	if o.MaxExponent != 0 {
+		exponent += ", maxexponent=" + strconv.FormatFloat(o.MaxExponent, 'g', -1, 64)
+	} else if o.Exponent == 1 {
+		name, exponent = "constant", ""
+	}
+
+	min := ""
+	if o.MinInterval != 0 {
+		min = ", min=" + o.MinInterval.String()
+	}
+
+	timeout := ""
+	if o.AttemptTimeout != 0 {
+		timeout = ", timeout=" + o.AttemptTimeout.String()
+	}
+
+	limit := "retries=" + strconv.Itoa(o.Retries)
+	if o.MaxAttempts != 0 {
+		limit = "attempts=" + strconv.Itoa(o.MaxAttempts)
+	}
+
+	return fmt.Sprintf("%s(base=%s, max=%s%s, budget=%s%s, %s, jitter=%s%s)",
+		name, o.Base, o.MaxInterval, min, o.MaxWait, timeout, limit,
+		strconv.FormatFloat(o.Jitter, 'g', -1, 64), exponent)
+}
+
+/*
+	UnmarshalText sets the fields of o named by the policy string in text,
+	along with Exponent, which the policy's name implies, and MaxInterval
+	for a constant policy without max. See Parse for the format. Other
+	fields are left unchanged, like UnmarshalJSON does for missing fields,
+	so a policy can adjust a set of defaults.
+*/
+func (o *Options) UnmarshalText(text []byte) error {
+
+	p, err := parse(*o, string(text))
+	if err != nil {
+		return err
+	}
+	*o = p
+
+	return nil
+}
@@ -0,0 +1,135 @@
+package retry
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+
+	cases := []struct {
+		policy  string
+		wantErr bool
+		want    Options
+	}{
+		/*
+		   Should return errors.
+		*/
+		{"", true, Options{}},
+		{"exponential", true, Options{}},
+		{"linear(base=50ms)", true, Options{}},
+		{"exponential(base)", true, Options{}},
+		{"exponential(base=fast)", true, Options{}},
+		{"exponential(base=50ms, base=60ms)", true, Options{}},
+		{"exponential(delay=50ms)", true, Options{}},
+		{"constant(base=50ms, exponent=2)", true, Options{}},
+		{"constant(base=50ms, maxexponent=2)", true, Options{}},
+
+		/*
+		   Should not return errors.
+		*/
+		{"exponential(base=50ms, max=1s, budget=2s, retries=3, jitter=0.5)", false, Options{
+			Retries:     3,
+			Base:        time.Millisecond * 50,
+			MaxInterval: time.Second * 1,
+			MaxWait:     time.Second * 2,
+			Exponent:    2,
+			Jitter:      0.5,
+		}},
+		{" exponential( exponent=1.5 ) ", false, Options{
+			Exponent: 1.5,
+		}},
+		{"constant(base=100ms, budget=1s, retries=5)", false, Options{
+			Retries:     5,
+			Base:        time.Millisecond * 100,
+			MaxInterval: time.Millisecond * 100,
+			MaxWait:     time.Second * 1,
+			Exponent:    1,
+		}},
+		{"exponential(base=50ms, max=1s, min=10ms, jitter=1)", false, Options{
+			Base:        time.Millisecond * 50,
+			MaxInterval: time.Second * 1,
+			MinInterval: time.Millisecond * 10,
+			Exponent:    2,
+			Jitter:      1,
+		}},
+		{"exponential(base=10ms, max=1s, budget=5s, timeout=500ms, exponent=1, maxexponent=3)", false, Options{
+			Base:           time.Millisecond * 10,
+			MaxInterval:    time.Second * 1,
+			MaxWait:        time.Second * 5,
+			AttemptTimeout: time.Millisecond * 500,
+			Exponent:       1,
+			MaxExponent:    3,
+		}},
+		{"constant(base=10ms, attempts=4)", false, Options{
+			MaxAttempts: 4,
+			Base:        time.Millisecond * 10,
+			MaxInterval: time.Millisecond * 10,
+			Exponent:    1,
+		}},
+		{"constant()", false, Options{
+			Exponent: 1,
+		}},
+	}
+
+	for _, c := range cases {
+		got, err := Parse(c.policy)
+		if c.wantErr != (err != nil) || !c.wantErr && !sameOptions(got, c.want) {
+			t.Errorf(
+				"Parse(%q)\n"+
+					"    return %+v, %v\n"+
+					"    wanted %+v, error: %t\n",
+				c.policy, got, err, c.want, c.wantErr)
+		}
+
+		// String produces a policy that parses to the same Options.
+		if !c.wantErr {
+			if again, err := Parse(c.want.String()); err != nil || !sameOptions(again, c.want) {
+				t.Errorf("Parse(%q) = %+v, %v, wanted %+v, nil", c.want.String(), again, err, c.want)
+			}
+		}
+	}
+
+	o := Options{Base: time.Millisecond * 50, MaxInterval: time.Second, Exponent: 1.5, Jitter: 0.25}
+	want := "exponential(base=50ms, max=1s, budget=0s, retries=0, jitter=0.25, exponent=1.5)"
+	if got := o.String(); got != want {
+		t.Errorf("Options.String() = %s, wanted %s", got, want)
+	}
+
+	o = Options{MaxAttempts: 3, Exponent: 1}
+	want = "constant(base=0s, max=0s, budget=0s, attempts=3, jitter=0)"
+	if got := o.String(); got != want {
+		t.Errorf("Options.String() = %s, wanted %s", got, want)
+	}
+
+	o = Options{Exponent: 1, MaxExponent: 3, AttemptTimeout: time.Second}
+	want = "exponential(base=0s, max=0s, budget=0s, timeout=1s, retries=0, jitter=0, exponent=1, maxexponent=3)"
+	if got := o.String(); got != want {
+		t.Errorf("Options.String() = %s, wanted %s", got, want)
+	}
+}
+
+func TestUnmarshalText(t *testing.T) {
+
+	// Fields the policy doesn't name are left alone.
+	o := Options{MaxAttempts: 4, MaxInterval: time.Second, MaxWait: time.Minute, Jitter: 0.5}
+	if err := o.UnmarshalText([]byte("exponential(base=50ms, retries=3)")); err != nil {
+		t.Fatal(err)
+	}
+	want := Options{
+		Retries:     3,
+		Base:        time.Millisecond * 50,
+		MaxInterval: time.Second,
+		MaxWait:     time.Minute,
+		Exponent:    2,
+		Jitter:      0.5,
+	}
+	if !sameOptions(o, want) {
+		t.Errorf("UnmarshalText into Options = %+v, wanted %+v", o, want)
+	}
+
+	// Options are unchanged by an invalid policy.
+	if err := o.UnmarshalText([]byte("exponential(base=1ms, retries=x)")); err == nil || !sameOptions(o, want) {
+		t.Errorf("UnmarshalText of an invalid policy returned %v and left %+v, wanted an error and %+v", err, o, want)
+	}
+}
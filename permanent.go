@@ -0,0 +1,75 @@
+package retry
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+/*
+	Permanent wraps err to mark it as permanent. When an operation returns
+	an error wrapped by Permanent, Try stops with ErrCancelled without
+	consulting the Retry passed to New. This lets helpers that accept a
+	Tryer classify the errors of the operations they run themselves.
+
+	The error recorded in the slice of errors returned by Try is err, not
+	the wrapper. Permanent returns nil if err is nil.
+*/
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err}
+}
+
+/*
+	IsPermanent reports whether err, or any error it wraps, was marked
+	permanent with Permanent.
+*/
+func IsPermanent(err error) bool {
+	_, ok := unwrapPermanent(err)
+	return ok
+}
+
+/*
+	unwrapPermanent returns the error wrapped by Permanent and true, or
+	err and false if err was not marked permanent.
+*/
+func unwrapPermanent(err error) (error, bool) {
+	if p := findPermanent(err); p != nil {
+		return p.err, true
+	}
+	return err, false
+}
+
+/*
+	findPermanent walks the tree of errors wrapped by err like errors.As
+	does. It avoids errors.As because that makes its target escape to the
+	heap, costing an allocation for every failed attempt.
+*/
+func findPermanent(err error) *permanentError {
+	for err != nil {
+		switch e := err.(type) {
+		case *permanentError:
+			return e
+		case interface{ Unwrap() error }:
+			err = e.Unwrap()
+		case interface{ Unwrap() []error }:
+			for _, err := range e.Unwrap() {
+				if p := findPermanent(err); p != nil {
+					return p
+				}
+			}
+			return nil
+		default:
+			return nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package retry
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestIsPermanent(t *testing.T) {
+
+	errTest := errors.New("test")
+
+	cases := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{errTest, false},
+		{Permanent(errTest), true},
+		{fmt.Errorf("wrapped: %w", Permanent(errTest)), true},
+		{errors.Join(errTest, fmt.Errorf("wrapped: %w", Permanent(errTest))), true},
+		{errors.Join(errTest, errTest), false},
+	}
+
+	for _, c := range cases {
+		if got := IsPermanent(c.err); got != c.want {
+			t.Errorf("IsPermanent(%v) = %t, wanted %t", c.err, got, c.want)
+		}
+	}
+}
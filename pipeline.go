@@ -0,0 +1,120 @@
+package retry
+
+import (
+	"context"
+	"time"
+)
+
+/*
+	Pipeline composes a Tryer with the other components commonly used to
+	call an unreliable dependency, so every call site applies them in the
+	same order. Build one with NewPipeline and its setter methods, then
+	call Execute:
+
+		p := retry.NewPipeline(t).
+			Timeout(time.Second).
+			Breaker(breaker).
+			Bulkhead(bulkhead).
+			Fallback(useCache)
+
+		err := p.Execute(ctx, fetch)
+
+	From the outside in, Execute runs:
+
+		Fallback   handles the error if the Tryer gives up
+		Tryer      retries with its Options, including its Middleware
+		Breaker    rejects attempts while the dependency is unhealthy
+		Bulkhead   rejects attempts while too many are in flight
+		Timeout    limits how long each attempt may take
+		fn         the operation
+
+	so a rejected attempt never takes a Bulkhead slot and only the call to
+	fn counts against the Timeout. Every component other than the Tryer is
+	optional. A Pipeline must not be changed while Execute is running but
+	is otherwise safe for concurrent use.
+*/
+type Pipeline struct {
+	tryer    *Tryer
+	timeout  time.Duration
+	breaker  Middleware
+	bulkhead *Bulkhead
+	fallback func(ctx context.Context, err error) error
+}
+
+/*
+	NewPipeline returns a Pipeline that retries with t.
+*/
+func NewPipeline(t *Tryer) *Pipeline {
+	return &Pipeline{tryer: t}
+}
+
+/*
+	Timeout sets the longest each attempt may take. The context passed to
+	the operation is cancelled after d.
+*/
+func (p *Pipeline) Timeout(d time.Duration) *Pipeline {
+	p.timeout = d
+	return p
+}
+
+/*
+	Breaker sets a circuit breaker, written as a Middleware that returns an
+	error without calling next while the circuit is open and records the
+	result of each attempt it lets through.
+*/
+func (p *Pipeline) Breaker(m Middleware) *Pipeline {
+	p.breaker = m
+	return p
+}
+
+/*
+	Bulkhead sets the Bulkhead limiting attempts in flight.
+*/
+func (p *Pipeline) Bulkhead(b *Bulkhead) *Pipeline {
+	p.bulkhead = b
+	return p
+}
+
+/*
+	Fallback sets a function called with the overall error when the Tryer
+	gives up. Its result is returned by Execute in place of that error, so
+	it can return nil after serving a cached or default value.
+*/
+func (p *Pipeline) Fallback(fn func(ctx context.Context, err error) error) *Pipeline {
+	p.fallback = fn
+	return p
+}
+
+/*
+	Execute calls fn through every component of p. It returns nil if an
+	attempt succeeded, and otherwise the overall error from the Tryer, or
+	the result of the Fallback if one is set.
+*/
+func (p *Pipeline) Execute(ctx context.Context, fn OperationCtx) error {
+
+	if fn == nil {
+		return errNoFunc
+	}
+
+	op := fn
+	if p.timeout > 0 {
+		next := op
+		op = func(ctx context.Context) error {
+			ctx, cancel := context.WithTimeout(ctx, p.timeout)
+			defer cancel()
+			return next(ctx)
+		}
+	}
+	if p.bulkhead != nil {
+		op = p.bulkhead.Middleware()(op)
+	}
+	if p.breaker != nil {
+		op = p.breaker(op)
+	}
+
+	_, err := p.tryer.TryContext(ctx, op)
+	if err != nil && p.fallback != nil {
+		return p.fallback(ctx, err)
+	}
+	return err
+}
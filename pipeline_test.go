@@ -0,0 +1,69 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestPipeline(t *testing.T) {
+
+	tryer, err := New(nil, Options{
+		Retries:     2,
+		Base:        time.Millisecond * 1,
+		MaxInterval: time.Millisecond * 5,
+		MaxWait:     time.Second * 1,
+		Exponent:    2,
+	})
+	if err != nil {
+		t.Fatal("Failed to initialise Tryer while testing Pipeline:\n    ", err.Error())
+	}
+
+	bulkhead, err := NewBulkhead(1)
+	if err != nil {
+		t.Fatal("Failed to initialise Bulkhead:\n    ", err.Error())
+	}
+
+	var order []string
+	breaker := func(next OperationCtx) OperationCtx {
+		return func(ctx context.Context) error {
+			order = append(order, fmt.Sprintf("breaker in-flight=%d", bulkhead.InFlight()))
+			return next(ctx)
+		}
+	}
+
+	errFallback := errors.New("fallback")
+	var fellBack error
+	p := NewPipeline(tryer).
+		Timeout(time.Millisecond * 10).
+		Breaker(breaker).
+		Bulkhead(bulkhead).
+		Fallback(func(ctx context.Context, err error) error {
+			fellBack = err
+			return errFallback
+		})
+
+	err = p.Execute(context.Background(), func(ctx context.Context) error {
+		_, hasDeadline := ctx.Deadline()
+		order = append(order, fmt.Sprintf("fn in-flight=%d deadline=%t", bulkhead.InFlight(), hasDeadline))
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	if err != errFallback || !errors.Is(fellBack, ErrMaxRetries) {
+		t.Errorf("Execute returned %v with fallback given %v, wanted %v with %v",
+			err, fellBack, errFallback, ErrMaxRetries)
+	}
+	want := "[breaker in-flight=0 fn in-flight=1 deadline=true " +
+		"breaker in-flight=0 fn in-flight=1 deadline=true " +
+		"breaker in-flight=0 fn in-flight=1 deadline=true]"
+	if got := fmt.Sprint(order); got != want {
+		t.Errorf("Execute ran\n    %s\nwanted\n    %s", got, want)
+	}
+
+	if err := NewPipeline(tryer).Execute(context.Background(), func(context.Context) error { return nil }); err != nil {
+		t.Errorf("Execute on a Pipeline with only a Tryer returned %v, wanted nil", err)
+	}
+}
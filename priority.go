@@ -0,0 +1,36 @@
+package retry
+
+import "context"
+
+/*
+	Priority ranks calls to TryContext for the purposes of a Throttle.
+	When a Throttle runs low on tokens it stops retrying low priority
+	calls first, then normal ones, and high priority calls last.
+*/
+type Priority int
+
+const (
+	PriorityLow    Priority = -1
+	PriorityNormal Priority = 0
+	PriorityHigh   Priority = 1
+)
+
+type priorityKey struct{}
+
+/*
+	WithPriority returns a copy of ctx tagged with p. Calls to TryContext
+	given the returned context are throttled according to p. Contexts not
+	tagged have PriorityNormal.
+*/
+func WithPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, p)
+}
+
+/*
+	PriorityFromContext returns the priority ctx was tagged with by
+	WithPriority, or PriorityNormal if it was not tagged.
+*/
+func PriorityFromContext(ctx context.Context) Priority {
+	p, _ := ctx.Value(priorityKey{}).(Priority)
+	return p
+}
@@ -0,0 +1,51 @@
+package retry
+
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+	"math/rand/v2"
+	"sync"
+)
+
+/*
+	globalSource is a rand.Source backed by the top level functions of
+	math/rand/v2, which are safe for concurrent use and seeded randomly
+	for each process, so calls sharing a Tryer don't contend on a lock.
+*/
+type globalSource struct{}
+
+func (globalSource) Uint64() uint64 {
+	return rand.Uint64()
+}
+
+/*
+	lockedSource serialises access to a rand.Source supplied in Options
+	since Tryers are used from many goroutines at once.
+*/
+type lockedSource struct {
+	mu  sync.Mutex
+	src rand.Source
+}
+
+func (s *lockedSource) Uint64() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.src.Uint64()
+}
+
+/*
+	CryptoSource is a rand.Source that reads from crypto/rand. Use it as
+	Options.Source where predictable retry timing would leak information,
+	or where many processes might otherwise start with the same state,
+	such as serverless functions that cold start together. It is slower
+	than the default source.
+*/
+type CryptoSource struct{}
+
+func (CryptoSource) Uint64() uint64 {
+	var b [8]byte
+	if _, err := crand.Read(b[:]); err != nil {
+		panic("retry: couldn't read random bytes: " + err.Error())
+	}
+	return binary.LittleEndian.Uint64(b[:])
+}
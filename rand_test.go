@@ -0,0 +1,93 @@
+package retry
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"testing"
+	"time"
+)
+
+func TestSource(t *testing.T) {
+
+	delays := func() []time.Duration {
+		tryer, err := New(nil, Options{
+			Base:        time.Millisecond * 10,
+			MaxInterval: time.Second * 1,
+			Exponent:    2,
+			Jitter:      1,
+			Source:      rand.NewPCG(1, 2),
+		})
+		if err != nil {
+			t.Fatal("Failed to initialise Tryer while testing Source:\n    ", err.Error())
+		}
+		var d []time.Duration
+		for retry := 0; retry < 5; retry++ {
+			d = append(d, tryer.Delay(retry))
+		}
+		return d
+	}
+
+	a, b := delays(), delays()
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("Delays with the same Source differ: %v and %v", a, b)
+		}
+	}
+}
+
+func TestSeed(t *testing.T) {
+
+	delays := func(seed uint64) []time.Duration {
+		tryer, err := New(nil, Options{
+			Base:        time.Millisecond * 10,
+			MaxInterval: time.Second * 1,
+			Exponent:    2,
+			Jitter:      1,
+			Seed:        seed,
+		})
+		if err != nil {
+			t.Fatal("Failed to initialise Tryer while testing Seed:\n    ", err.Error())
+		}
+		var d []time.Duration
+		for retry := 0; retry < 5; retry++ {
+			d = append(d, tryer.Delay(retry))
+		}
+		return d
+	}
+
+	a, b, c := delays(1), delays(1), delays(2)
+	if fmt.Sprint(a) != fmt.Sprint(b) {
+		t.Errorf("Delays with the same Seed differ: %v and %v", a, b)
+	}
+	if fmt.Sprint(a) == fmt.Sprint(c) {
+		t.Errorf("Delays with different Seeds are the same: %v", a)
+	}
+}
+
+func TestCryptoSource(t *testing.T) {
+
+	o := Options{
+		Base:        time.Millisecond * 10,
+		MaxInterval: time.Second * 1,
+		Exponent:    2,
+		Jitter:      1,
+		Source:      CryptoSource{},
+	}
+
+	tryer, err := New(nil, o)
+	if err != nil {
+		t.Fatal("Failed to initialise Tryer while testing CryptoSource:\n    ", err.Error())
+	}
+
+	seen := map[time.Duration]bool{}
+	for i := 0; i < 20; i++ {
+		d := tryer.Delay(0)
+		if d < 0 || d > o.Base {
+			t.Fatalf("Delay(0) = %s, wanted between 0 and %s", d, o.Base)
+		}
+		seen[d] = true
+	}
+	if len(seen) < 2 {
+		t.Errorf("Delay(0) always returned the same delay, wanted jitter")
+	}
+}
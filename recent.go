@@ -0,0 +1,94 @@
+package retry
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+/*
+	Outcome describes how a call to Try or one of its variants ended. See
+	Options.RecentOutcomes.
+*/
+type Outcome struct {
+	Time      time.Time     // When the call returned.
+	Operation string        // See WithOperation.
+	Attempts  int           // Attempts made, including any before Resume.
+	Elapsed   time.Duration // Time taken by the call.
+	Err       error         // The error returned, or nil on success.
+}
+
+/*
+	recent is a ring of the last Outcomes of a Tryer's calls.
+*/
+type recent struct {
+	mu   sync.Mutex
+	buf  []Outcome
+	next int
+	full bool
+}
+
+/*
+	add records o, keeping at most size Outcomes. When size changes the
+	Outcomes recorded so far are discarded.
+*/
+func (r *recent) add(size int, o Outcome) {
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.buf) != size {
+		r.buf = make([]Outcome, size)
+		r.next = 0
+		r.full = false
+	}
+
+	r.buf[r.next] = o
+	r.next++
+	if r.next == size {
+		r.next = 0
+		r.full = true
+	}
+}
+
+func (r *recent) list() []Outcome {
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.full {
+		return append([]Outcome(nil), r.buf[:r.next]...)
+	}
+	out := make([]Outcome, 0, len(r.buf))
+	out = append(out, r.buf[r.next:]...)
+	return append(out, r.buf[:r.next]...)
+}
+
+/*
+	Recent returns the Outcomes of the latest calls made with t, oldest
+	first, if Options.RecentOutcomes is set. It is meant for diagnostics
+	such as showing what has been failing in the last few minutes.
+*/
+func (t *Tryer) Recent() []Outcome {
+	return t.recent.list()
+}
+
+type operationKey struct{}
+
+/*
+	WithOperation returns a copy of ctx naming the operation a call to
+	TryContext performs, such as "GetUser", so that its Outcome can be
+	told apart from those of other operations sharing the Tryer.
+*/
+func WithOperation(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, operationKey{}, name)
+}
+
+/*
+	OperationFromContext returns the name ctx was given by WithOperation,
+	or "" if it has none.
+*/
+func OperationFromContext(ctx context.Context) string {
+	name, _ := ctx.Value(operationKey{}).(string)
+	return name
+}
@@ -0,0 +1,68 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRecent(t *testing.T) {
+
+	tryer, err := New(nil, Options{
+		Retries:        1,
+		Base:           time.Millisecond * 1,
+		MaxInterval:    time.Millisecond * 5,
+		MaxWait:        time.Second * 1,
+		Exponent:       2,
+		RecentOutcomes: 3,
+	})
+	if err != nil {
+		t.Fatal("Failed to initialise Tryer while testing Recent:\n    ", err.Error())
+	}
+
+	if got := tryer.Recent(); len(got) != 0 {
+		t.Fatalf("Recent before any calls returned %d outcomes, wanted 0", len(got))
+	}
+
+	ops := []string{"a", "b", "c", "d"}
+	for i, op := range ops {
+		ctx := WithOperation(context.Background(), op)
+		_, _ = tryer.TryContext(ctx, func(ctx context.Context) error {
+			// Odd numbered calls fail.
+			if i%2 == 1 {
+				return errors.New("test")
+			}
+			return nil
+		})
+	}
+
+	got := tryer.Recent()
+	if len(got) != 3 {
+		t.Fatalf("Recent returned %d outcomes, wanted 3", len(got))
+	}
+
+	want := []struct {
+		op       string
+		attempts int
+		failed   bool
+	}{
+		{"b", 2, true},
+		{"c", 1, false},
+		{"d", 2, true},
+	}
+	for i, w := range want {
+		o := got[i]
+		if o.Operation != w.op || o.Attempts != w.attempts || (o.Err != nil) != w.failed {
+			t.Errorf("Recent()[%d] = %+v, wanted operation %q with %d attempts and failed = %t",
+				i, o, w.op, w.attempts, w.failed)
+		}
+		if i > 0 && o.Time.Before(got[i-1].Time) {
+			t.Errorf("Recent()[%d] is older than the outcome before it", i)
+		}
+	}
+
+	if _, err := New(nil, Options{Base: 1, MaxInterval: 1, Exponent: 1, RecentOutcomes: -1}); err == nil {
+		t.Error("New accepted a negative RecentOutcomes")
+	}
+}
@@ -0,0 +1,106 @@
+package retry
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+/*
+	Registry holds named Tryers so that an application can define its retry
+	policies once at startup, e.g. "db", "payment-api", and "s3", and share
+	them wherever they are needed. A Registry is safe for concurrent use.
+*/
+type Registry struct {
+	mu     sync.RWMutex
+	tryers map[string]*Tryer
+}
+
+/*
+	DefaultRegistry is a Registry available to the whole program.
+*/
+var DefaultRegistry = NewRegistry()
+
+/*
+	NewRegistry returns an empty Registry.
+*/
+func NewRegistry() *Registry {
+	return &Registry{tryers: map[string]*Tryer{}}
+}
+
+/*
+	Register adds t to the Registry under name. It returns an error if name
+	is empty, t is nil, or a Tryer is already registered under name.
+*/
+func (r *Registry) Register(name string, t *Tryer) error {
+
+	if name == "" {
+		return fmt.Errorf("expected a name to register Tryer under, got \"\"")
+	}
+	if t == nil {
+		return fmt.Errorf("expected a Tryer to register under %q, got nil", name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.tryers[name]; ok {
+		return fmt.Errorf("a Tryer is already registered under %q", name)
+	}
+	r.tryers[name] = t
+	return nil
+}
+
+/*
+	Get returns the Tryer registered under name. The ok result is false if
+	there is none.
+*/
+func (r *Registry) Get(name string) (t *Tryer, ok bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	t, ok = r.tryers[name]
+	return t, ok
+}
+
+/*
+	Update replaces the Options of the Tryer registered under name. See
+	Tryer.Update, which replaces hooks too, for how to reload only the
+	fields in a configuration file. It returns an error if there is no
+	Tryer registered under name or if o is invalid.
+*/
+func (r *Registry) Update(name string, o Options) error {
+	t, ok := r.Get(name)
+	if !ok {
+		return fmt.Errorf("no Tryer is registered under %q", name)
+	}
+	return t.Update(o)
+}
+
+/*
+	Names returns the names of the registered Tryers in sorted order.
+*/
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.tryers))
+	for name := range r.tryers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+/*
+	Snapshot returns a copy of the Registry's contents at the time it was
+	called, for diagnostics. Changes to the Registry after Snapshot returns
+	aren't reflected in the map.
+*/
+func (r *Registry) Snapshot() map[string]*Tryer {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	m := make(map[string]*Tryer, len(r.tryers))
+	for name, t := range r.tryers {
+		m[name] = t
+	}
+	return m
+}
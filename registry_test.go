@@ -0,0 +1,60 @@
+package retry
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestRegistry(t *testing.T) {
+
+	tryer, err := New(nil, Options{
+		Retries:     3,
+		Base:        time.Millisecond * 30,
+		MaxInterval: time.Second * 1,
+		MaxWait:     time.Second * 2,
+		Exponent:    2,
+	})
+	if err != nil {
+		t.Fatal("Failed to initialise Tryer while testing Registry:\n    ", err.Error())
+	}
+
+	r := NewRegistry()
+
+	cases := []struct {
+		name    string
+		tryer   *Tryer
+		wantErr bool
+	}{
+		{"db", tryer, false},
+		{"s3", tryer, false},
+		{"db", tryer, true},
+		{"", tryer, true},
+		{"payment-api", nil, true},
+	}
+
+	for _, c := range cases {
+		if err := r.Register(c.name, c.tryer); c.wantErr != (err != nil) {
+			t.Errorf("Registry.Register(%q, %p) returned %v, wanted error: %t",
+				c.name, c.tryer, err, c.wantErr)
+		}
+	}
+
+	if got, ok := r.Get("db"); !ok || got != tryer {
+		t.Errorf("Registry.Get(%q) = %p, %t, wanted %p, true", "db", got, ok, tryer)
+	}
+	if _, ok := r.Get("payment-api"); ok {
+		t.Errorf("Registry.Get(%q) returned ok, wanted !ok", "payment-api")
+	}
+
+	if err := r.Update("payment-api", Options{Exponent: 2}); err == nil {
+		t.Errorf("Registry.Update(%q) returned nil error, wanted error", "payment-api")
+	}
+
+	if got := fmt.Sprint(r.Names()); got != "[db s3]" {
+		t.Errorf("Registry.Names() = %s, wanted [db s3]", got)
+	}
+	if got := r.Snapshot(); len(got) != 2 || got["s3"] != tryer {
+		t.Errorf("Registry.Snapshot() = %v, wanted db and s3", got)
+	}
+}
@@ -59,11 +59,14 @@ fail, using exponential backoff and jittering between attempts.
 package retry
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math"
-	"math/rand"
+	"math/rand/v2"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -82,11 +85,28 @@ var ErrCancelled = errors.New("further retries cancelled")
 
 /*
 	ErrTimeout is returned from Try when the total elapsed time
-	attempting the operation exceeds the maximum alloted wait
+	attempting the operation would exceed the maximum alloted wait
 	time specified by .MaxWait in Options.
 */
 var ErrTimeout = errors.New("couldn't complete operation in time")
 
+/*
+	ErrThrottled is returned from Try when it stops retrying because the
+	Throttle in Options reports that too many attempts have been failing,
+	because Options.SlowStart is holding retries back, because
+	Options.MaxRetriesPerWindow has been reached, because a Hint from
+	the server asked for the call's priority to be shed, or because
+	Options.BurnRate has reduced the retries allowed.
+*/
+var ErrThrottled = errors.New("retries throttled")
+
+/*
+	ErrRepeatedFailure is returned from Try when an operation fails with
+	the same error as many times in a row as Options.MaxRepeats allows,
+	suggesting the failure is deterministic and retrying won't help.
+*/
+var ErrRepeatedFailure = errors.New("operation failed the same way repeatedly")
+
 /*
 	errNoFunc is returned by Try when fn is nil - it's a global
 	to make testing easier.
@@ -101,6 +121,21 @@ var errNoFunc = errors.New("fn is nil")
 */
 type Retry = func(err error) (tryAgain bool)
 
+/*
+	BeforeAttempt is a hook called before each attempt of an operation
+	with the context that will be passed to the operation. The context it
+	returns is passed to the operation in its place, allowing values such
+	as credentials or request identifiers to be attached per attempt.
+*/
+type BeforeAttempt = func(ctx context.Context) context.Context
+
+/*
+	Middleware wraps an operation to add behaviour around each attempt,
+	such as tracing or refreshing credentials, in the same way HTTP
+	middleware wraps a handler. It should call next to run the attempt.
+*/
+type Middleware = func(next OperationCtx) OperationCtx
+
 type Options struct {
 	/*
 		Retries is a value of 0 or greater that determines the maximum
@@ -112,6 +147,16 @@ type Options struct {
 	*/
 	Retries int
 
+	/*
+		MaxAttempts is an alternative to Retries for those used to counting
+		the initial attempt, as some other libraries do. When it is greater
+		than 0 it is the maximum number of times an operation will be
+		called in total, so a MaxAttempts of 3 is the same as a Retries of 2.
+		Every attempt counts unless UncountedTimeouts is set. An error is
+		returned by New if both Retries and MaxAttempts are set.
+	*/
+	MaxAttempts int
+
 	/*
 		Base determines the initial delay before retrying an operation.
 	*/
@@ -123,12 +168,55 @@ type Options struct {
 	*/
 	MaxInterval time.Duration
 
+	/*
+		MinInterval is an optional value between 0 and Base that determines
+		the shortest possible time Try will wait between calls. Delays are
+		raised to MinInterval after Jitter is applied, so a large Jitter
+		can't make Try call a struggling dependency again almost straight
+		away.
+	*/
+	MinInterval time.Duration
+
 	/*
 		MaxWait is a value greater than or equal to Base that determines the
 		maximum time Try will spend trying to successfully execute its operation.
+		Try gives up as soon as waiting for the next attempt and then running
+		it for the usual time (see Tryer.AttemptDuration) would exceed MaxWait,
+		rather than making an attempt that can't finish in time.
 	*/
 	MaxWait time.Duration
 
+	/*
+		AttemptTimeout optionally limits how long each attempt made by
+		TryContext or Resume may take. When it is greater than 0 the context
+		passed to the operation has a deadline of AttemptTimeout or the
+		time left before MaxWait, whichever is sooner, so the last attempt
+		only gets the time that remains. An attempt is failed with
+		context.DeadlineExceeded without being made if no time is left, and
+		a MaxWait of 0 doesn't limit the deadline. A deadline on the context
+		passed to TryContext still applies if it is sooner again.
+	*/
+	AttemptTimeout time.Duration
+
+	/*
+		CheckAttemptTimeouts passes errors matching context.DeadlineExceeded
+		to the Retry given to New like any other error. By default they are
+		always retried without consulting Retry, provided the context passed
+		to TryContext is still live, since an attempt that timed out on its
+		own deadline, such as AttemptTimeout, usually failed transiently.
+	*/
+	CheckAttemptTimeouts bool
+
+	/*
+		UncountedTimeouts stops attempts that fail with an error matching
+		context.DeadlineExceeded, while the context passed to TryContext is
+		still live, from counting against Retries or MaxAttempts. This
+		suits an AttemptTimeout short enough that a slow dependency might
+		use up every attempt before failing properly. MaxWait still limits
+		how long Try keeps retrying them.
+	*/
+	UncountedTimeouts bool
+
 	/*
 		Exponent is a value greater than 1 that determines the growth rate of
 		the interval between retries. For example an Exponent of 2 would double
@@ -137,6 +225,15 @@ type Options struct {
 	*/
 	Exponent float64
 
+	/*
+		MaxExponent optionally turns Exponent into a range. When it is
+		greater than 0 the growth rate is chosen at random between Exponent
+		and MaxExponent for every delay, which decorrelates clients further
+		than Jitter alone. An error is returned by New if MaxExponent is
+		greater than 0 but less than Exponent.
+	*/
+	MaxExponent float64
+
 	/*
 	   Jitter is a value between 0 and 1 which is used to determine
 	   how much randomness affects intervals between retries.
@@ -153,6 +250,232 @@ type Options struct {
 	   than 1.
 	*/
 	Jitter float64
+
+	/*
+		SkipFirstJitter applies no Jitter to the delay before the first
+		retry, so it is always Base and easy to recognise in traces, while
+		later retries are jittered as usual to spread them out.
+	*/
+	SkipFirstJitter bool
+
+	/*
+		Source optionally supplies the random numbers used for Jitter and
+		MaxExponent, for example rand.NewPCG with fixed seeds so a test gets
+		the same delays every time it runs. Access to Source is serialised
+		so it doesn't need to be safe for concurrent use. If Source is nil
+		the top level functions of math/rand/v2 are used.
+	*/
+	Source rand.Source
+
+	/*
+		Seed optionally makes the delays of a Tryer reproducible, for
+		example to replay an incident or write golden tests. If it isn't 0
+		and Source is nil, Source is a rand.PCG seeded with Seed. The
+		sequence starts again from Seed whenever Tryer.Update is called.
+	*/
+	Seed uint64
+
+	/*
+		BeforeAttempt is an optional hook called before each attempt. See
+		BeforeAttempt for more information.
+	*/
+	BeforeAttempt BeforeAttempt
+
+	/*
+		SetupAttempt optionally prepares resources for each attempt, such as
+		a temporary directory, a new request, or a reset buffer, so that no
+		attempt sees what an earlier one left behind. It is called after
+		BeforeAttempt with the attempt's context and returns the context to
+		pass to the operation, typically carrying the resources. If it
+		returns an error the attempt fails with that error without the
+		operation being called. Its time doesn't count against
+		AttemptTimeout.
+	*/
+	SetupAttempt func(ctx context.Context) (context.Context, error)
+
+	/*
+		TeardownAttempt optionally releases the resources made by
+		SetupAttempt. It is called after every attempt whose SetupAttempt
+		succeeded, or every attempt if SetupAttempt is nil, with the context
+		SetupAttempt returned and the attempt's error. It is called even if
+		the operation panics, with a non-nil error, before the panic
+		continues.
+	*/
+	TeardownAttempt func(ctx context.Context, err error)
+
+	/*
+		IdempotencyKey optionally generates a key identifying a single call
+		to Try or TryContext. It is called once per call, before the first
+		attempt, and the key is attached to the context of every attempt
+		so operations can send it to servers that deduplicate retried
+		requests. See NewIdempotencyKey and IdempotencyKeyFromContext.
+	*/
+	IdempotencyKey func() string
+
+	/*
+		Middleware is an optional chain wrapped around every attempt. The
+		first Middleware is the outermost, so it runs first and sees the
+		result of all the others. The chain is built once per call to Try
+		or TryContext, so a Middleware may keep state for that call in the
+		closure it returns.
+	*/
+	Middleware []Middleware
+
+	/*
+		GiveUp is an optional predicate called after a failed attempt that
+		would otherwise be retried. It receives the number of attempts made
+		so far and the time elapsed since the call began, and returning
+		true stops Try with ErrCancelled. This allows retries to end early
+		regardless of the error, for example to stop retrying a request a
+		user is waiting on after 800 milliseconds.
+	*/
+	GiveUp func(attempts int, elapsed time.Duration) bool
+
+	/*
+		MaxRepeats optionally stops Try with ErrRepeatedFailure once an
+		operation has failed with the same error this many times in a row.
+		Errors are the same if either wraps the other, as reported by
+		errors.Is, or if they have the same message. When it is 0 repeated
+		errors are retried as usual.
+	*/
+	MaxRepeats int
+
+	/*
+		OnRetry is an optional hook called after a failed attempt that will
+		be retried, before waiting s.NextDelay. It receives the state of the
+		current call and the error from the attempt. The state may be saved
+		and passed to Tryer.Resume to continue the call later.
+	*/
+	OnRetry func(s State, err error)
+
+	/*
+		OnSuccess is an optional hook called when an attempt succeeds. It
+		receives the state of the call, where s.Elapsed is the total time
+		including waiting between attempts, and took, the time the
+		successful attempt itself took. The difference shows how much
+		retrying added to the operation.
+	*/
+	OnSuccess func(s State, took time.Duration)
+
+	/*
+		OnExhausted is an optional hook called exactly once when a call to
+		Try gives up because it reached Options.Retries or Options.MaxWait.
+		It receives the operation and the errors from every attempt, and is
+		intended for alerting, persisting the failed work, or compensating
+		actions. It is not called when the operation is cancelled by Retry
+		or by a context. For calls to Try, fn calls the Operation passed
+		to Try.
+	*/
+	OnExhausted func(fn OperationCtx, errs []error)
+
+	/*
+		Adaptive optionally grows the Base interval while operations keep
+		failing and shrinks it again as they succeed. See Adaptive.
+	*/
+	Adaptive *Adaptive
+
+	/*
+		Throttle optionally suppresses retries while failures outweigh
+		successes. See Throttle.
+	*/
+	Throttle *Throttle
+
+	/*
+		SlowStart optionally ramps retries back up gradually after an
+		outage ends. See SlowStart.
+	*/
+	SlowStart *SlowStart
+
+	/*
+		Maintenance optionally reports planned downtime of the dependency,
+		so it doesn't cause retry storms and noisy alerts. While a window is
+		in progress, failed attempts aren't retried and Try stops with
+		ErrMaintenance, unless WaitOutMaintenance is set. First attempts are
+		still made. See Windows.
+	*/
+	Maintenance Maintenance
+
+	/*
+		WaitOutMaintenance makes Try extend the delay after a failed attempt
+		to the end of a maintenance window in progress rather than stop.
+		MaxWait still applies, so Try stops with ErrTimeout if the window
+		ends too late.
+	*/
+	WaitOutMaintenance bool
+
+	/*
+		Health optionally checks whether the dependency is up before each
+		retry, so an attempt known to fail isn't made. If it reports the
+		dependency is down after a failed attempt, Try stops with
+		ErrUnhealthy straight away rather than waiting, unless WaitForHealth
+		is set. First attempts are still made.
+	*/
+	Health HealthCheck
+
+	/*
+		WaitForHealth makes Try wait for the dependency to come back rather
+		than stop when Health reports it is down. Health is then checked
+		after each delay instead, and while it reports the dependency is
+		down Try waits again for the next delay of the backoff without
+		making an attempt or counting one against Retries. MaxWait still
+		applies, so Try stops with ErrTimeout if the dependency stays down.
+	*/
+	WaitForHealth bool
+
+	/*
+		MaxRetriesPerWindow optionally caps the number of retries all calls
+		sharing a Tryer may make in each Window, for example at most 10 a
+		minute, to protect a fragile dependency from the total volume of
+		retries regardless of Retries. Only retries are counted. Initial
+		attempts aren't counted or limited, and neither are retries that
+		Try abandons for another reason, such as MaxWait. Once the cap is
+		reached further retries stop with ErrThrottled until the next
+		window begins. New returns an error if only one of
+		MaxRetriesPerWindow and Window is set.
+	*/
+	MaxRetriesPerWindow int
+
+	/*
+		Window is the length of the windows MaxRetriesPerWindow applies to.
+		Each window begins with the first retry after the last one ended.
+	*/
+	Window time.Duration
+
+	/*
+		RecentOutcomes optionally keeps the Outcome of each of the latest
+		RecentOutcomes calls made with the Tryer, successful or not, to be
+		read with Tryer.Recent. It must be 0 or greater.
+	*/
+	RecentOutcomes int
+
+	/*
+		BurnRate optionally reports how fast the service is using up its
+		SLO error budget, as a multiple of the rate that would use it up
+		exactly by the end of the SLO period. While it is above 1 retries
+		are ramped down in proportion, so that retries don't deepen an
+		incident that is already costing the budget: Retries is divided by
+		the burn rate, rounded down, with further retries stopping with
+		ErrThrottled, and each delay is multiplied by it. For example at a
+		burn rate of 2 half the retries are made, twice as far apart. Retries
+		return to normal as the burn rate falls. BurnRate is called after
+		each failed attempt that could be retried, so it should be cheap,
+		such as reading a value a monitoring client refreshes periodically.
+	*/
+	BurnRate func() float64
+
+	/*
+		Coordinator optionally adjusts each delay so retries are spread
+		across every instance of a service. See Coordinator.
+	*/
+	Coordinator Coordinator
+
+	/*
+		Backoff optionally replaces the exponential backoff described by
+		Base, MaxInterval, Exponent, and Jitter, which are then ignored and
+		not validated by New. It is called once per call to Try to create
+		the Backoff for that call. Retries and MaxWait still apply.
+	*/
+	Backoff func() Backoff
 }
 
 /*
@@ -160,15 +483,28 @@ type Options struct {
 	new Tryer.
 */
 type Tryer struct {
+	retry    Retry
+	config   atomic.Pointer[config]
+	ewma     atomic.Int64
+	window   window
+	recent   recent
+	disabled atomic.Bool
+}
+
+/*
+	config holds the values of Options used by a Tryer. It is replaced
+	as a whole by Update so calls in progress see a consistent set.
+*/
+type config struct {
+	opts        Options
 	base        float64
 	maxInterval float64
+	minInterval float64
 	exponent    float64
 	jitter      float64
 	retries     int
 	maxWait     time.Duration
-	seed        int64
-	seedMu      sync.Mutex
-	retry       Retry
+	rand        *rand.Rand
 }
 
 /*
@@ -176,33 +512,191 @@ type Tryer struct {
 	if it is nil Try will always retry fn when it fails, up to o.Retries.
 	See Retry for more information.
 
-	New returns an error if the fields in o contain invalid values. See
-	Options for information on what the valid ranges are for each field.
+	New returns an *OptionsError if the fields in o contain invalid values.
+	See Options for information on what the valid ranges are for each field.
 */
 func New(retry Retry, o Options) (*Tryer, error) {
 
-	if o.Exponent < 1 {
-		return nil, fmt.Errorf(
+	c, err := newConfig(o)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &Tryer{
+		retry: retry,
+	}
+	t.config.Store(c)
+
+	return t, nil
+}
+
+/*
+	MustNew is like New but panics if o is invalid. It is meant for
+	initialising package level Tryers from Options fixed in code, where
+	invalid Options are a bug:
+
+		var dbTryer = retry.MustNew(nil, retry.Options{...})
+*/
+func MustNew(retry Retry, o Options) *Tryer {
+	return Must(New(retry, o))
+}
+
+/*
+	Must returns t, panicking if err is not nil. It wraps calls returning a
+	Tryer and an error, such as New or Builder.Tryer, in package level
+	variable declarations.
+*/
+func Must(t *Tryer, err error) *Tryer {
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+/*
+	RetryLimit returns the maximum number of retries after the initial
+	attempt allowed by o. This is MaxAttempts-1 when MaxAttempts is set and
+	Retries otherwise.
+*/
+func (o Options) RetryLimit() int {
+	if o.MaxAttempts > 0 {
+		return o.MaxAttempts - 1
+	}
+	return o.Retries
+}
+
+func newConfig(o Options) (*config, error) {
+
+	if o.Retries != 0 && o.MaxAttempts != 0 {
+		return nil, &OptionsError{
+			Fields:     []string{"Retries", "MaxAttempts"},
+			Values:     []any{o.Retries, o.MaxAttempts},
+			Constraint: "only one set",
+			msg: fmt.Sprintf("expected only one of .Retries and .MaxAttempts to be set, got %d and %d",
+				o.Retries, o.MaxAttempts),
+		}
+	}
+
+	if (o.MaxRetriesPerWindow > 0) != (o.Window > 0) {
+		return nil, &OptionsError{
+			Fields:     []string{"MaxRetriesPerWindow", "Window"},
+			Values:     []any{o.MaxRetriesPerWindow, o.Window},
+			Constraint: "both or neither greater than 0",
+			msg: fmt.Sprintf("expected both or neither of .MaxRetriesPerWindow and .Window to be greater than 0, got %d and %s",
+				o.MaxRetriesPerWindow, o.Window),
+		}
+	}
+
+	if o.RecentOutcomes < 0 {
+		return nil, optionsError("RecentOutcomes", o.RecentOutcomes, "0 or greater",
+			"expected .RecentOutcomes to be 0 or greater, got %d", o.RecentOutcomes)
+	}
+
+	if o.MaxAttempts < 0 {
+		return nil, optionsError("MaxAttempts", o.MaxAttempts, "0 or greater",
+			"expected .MaxAttempts to be 0 or greater, got %d", o.MaxAttempts)
+	}
+
+	retries := o.RetryLimit()
+
+	if o.Backoff == nil && o.Exponent < 1 {
+		return nil, optionsError("Exponent", o.Exponent, "at least 1",
 			"expected .Exponent to be greater than or equal to 1, got %.2f", o.Exponent)
 	}
 
-	if o.Jitter < 0 || o.Jitter > 1 {
-		return nil, fmt.Errorf("expected a .Jitter value between 0 and 1, got %.2f", o.Jitter)
+	if o.Backoff == nil && o.MaxExponent != 0 && o.MaxExponent < o.Exponent {
+		return nil, optionsError("MaxExponent", o.MaxExponent, "0 or at least Exponent",
+			"expected .MaxExponent to be 0 or greater than or equal to .Exponent (%.2f), got %.2f",
+			o.Exponent, o.MaxExponent)
 	}
 
-	return &Tryer{
-		seed:        time.Now().UnixNano(),
-		seedMu:      sync.Mutex{},
-		retries:     o.Retries,
+	if o.Backoff == nil && (o.Jitter < 0 || o.Jitter > 1) {
+		return nil, optionsError("Jitter", o.Jitter, "between 0 and 1",
+			"expected a .Jitter value between 0 and 1, got %.2f", o.Jitter)
+	}
+
+	if o.Backoff == nil && (o.MinInterval < 0 || o.MinInterval > o.Base) {
+		return nil, optionsError("MinInterval", o.MinInterval, "between 0 and Base",
+			"expected .MinInterval to be between 0 and .Base (%s), got %s", o.Base, o.MinInterval)
+	}
+
+	if o.Backoff == nil && o.Base > o.MaxInterval {
+		return nil, optionsError("MaxInterval", o.MaxInterval, "at least Base",
+			"expected .MaxInterval to be greater than or equal to .Base (%s), got %s", o.Base, o.MaxInterval)
+	}
+
+	src := rand.Source(globalSource{})
+	if o.Source != nil {
+		src = &lockedSource{src: o.Source}
+	} else if o.Seed != 0 {
+		src = &lockedSource{src: rand.NewPCG(o.Seed, o.Seed)}
+	}
+
+	return &config{
+		rand:        rand.New(src),
+		opts:        o,
+		retries:     retries,
 		base:        float64(o.Base),
 		maxInterval: float64(o.MaxInterval),
+		minInterval: float64(o.MinInterval),
 		maxWait:     o.MaxWait,
 		exponent:    o.Exponent,
 		jitter:      o.Jitter,
-		retry:       retry,
 	}, nil
 }
 
+/*
+	Update atomically replaces the Options of t, for example to loosen or
+	tighten retries during an incident without restarting. It returns an
+	error, leaving t unchanged, if o is invalid in the same way as for New.
+
+	Every field is replaced, including hooks such as OnRetry, Middleware,
+	Throttle and Health, so passing Options from FromConfig, FromEnv or
+	Parse drops them. To apply a configuration file on top of the current
+	Options instead, unmarshal it into them:
+
+		o := t.Options()
+		if err := json.Unmarshal(data, &o); err != nil {
+			return err
+		}
+		return t.Update(o)
+
+	Calls to Try that are in progress use the new Options from their next
+	attempt onwards, except for BeforeAttempt, SetupAttempt,
+	TeardownAttempt, IdempotencyKey, Middleware, and Backoff, which only
+	apply to calls made after Update returns.
+*/
+func (t *Tryer) Update(o Options) error {
+	c, err := newConfig(o)
+	if err != nil {
+		return err
+	}
+	t.config.Store(c)
+	return nil
+}
+
+/*
+	Options returns the Options t is currently using.
+*/
+func (t *Tryer) Options() Options {
+	return t.config.Load().opts
+}
+
+/*
+	String returns the policy of t in the format read by Parse.
+*/
+func (t *Tryer) String() string {
+	return t.Options().String()
+}
+
+/*
+	MarshalJSON encodes the policy of t as JSON, for example for a debug
+	endpoint. See Options.MarshalJSON.
+*/
+func (t *Tryer) MarshalJSON() ([]byte, error) {
+	return t.Options().MarshalJSON()
+}
+
 /*
 	Operation is a function passed to a Tryer's Try method. It will be executed
 	repeatedly until it returns nil or until it returns an error that Retry
@@ -210,63 +704,606 @@ func New(retry Retry, o Options) (*Tryer, error) {
 */
 type Operation = func() error
 
+/*
+	OperationCtx is a function passed to a Tryer's TryContext method. The
+	context it receives is the one passed to TryContext.
+*/
+type OperationCtx = func(ctx context.Context) error
+
 /*
 	Try calls fn repeatedly until it succeeds, or until fn returns an error
 	that the Retry passed to New decides is permanent, or until fn has been
 	called up to the maximum number of attempts specified in the Options
-	passed to New.
+	passed to New. Errors wrapped with Permanent are always treated as
+	permanent.
 
 	Try returns a slice of errors from calls to fn in the order they occured,
-	and an overall error from Try.
+	and an overall error from Try. When Try gives up on fn the overall error
+	is an *Error matching one of ErrCancelled, ErrTimeout, ErrMaxRetries,
+	ErrThrottled, ErrRepeatedFailure, ErrMaintenance, ErrUnhealthy, or
+	ErrDisabled.
 
 	The number of attempts for a failed operation (i.e., when err is not nil)
 	is always len(errs) while the number of attempts for a successful operation
 	(where err is nil) is always len(errs)+1.
+
+	A call to Try whose first attempt succeeds makes no heap allocations
+	unless Options has BeforeAttempt, SetupAttempt, TeardownAttempt,
+	IdempotencyKey, or Middleware set.
 */
-func (t Tryer) Try(fn Operation) (errs []error, err error) {
+func (t *Tryer) Try(fn Operation) (errs []error, err error) {
 
 	if fn == nil {
 		return errs, errNoFunc
 	}
 
+	return t.try(context.Background(), nil, fn, State{}, nil)
+}
+
+/*
+	TryContext is like Try but stops early when ctx is done, either before
+	an attempt or while waiting between attempts.
+
+	The context passed to fn is derived from ctx and carries metadata about
+	the current attempt. See AttemptFromContext, MaxAttemptsFromContext,
+	and ElapsedFromContext.
+
+	When ctx stops TryContext the overall error is an *Error matching
+	ctx.Err() that also wraps context.Cause(ctx), so the reason the parent
+	context was cancelled can be recovered with errors.Is or errors.As.
+	A deadline passing matches context.DeadlineExceeded and cancellation
+	matches context.Canceled; see also Error.Timeout.
+*/
+func (t *Tryer) TryContext(ctx context.Context, fn OperationCtx) (errs []error, err error) {
+	if fn == nil {
+		return errs, errNoFunc
+	}
+
+	return t.try(ctx, fn, nil, State{}, nil)
+}
+
+/*
+	Resume is like TryContext but continues a sequence of attempts from s,
+	which was typically reported to Options.OnRetry by an earlier call and
+	persisted, for example before the process restarted. Resume first waits
+	s.NextDelay and then makes attempt s.Attempts+1, so Retries and MaxWait
+	apply across both calls as though they were one.
+
+	The errs returned by Resume only include errors from its own attempts.
+*/
+func (t *Tryer) Resume(ctx context.Context, s State, fn OperationCtx) (errs []error, err error) {
+	if fn == nil {
+		return errs, errNoFunc
+	}
+
+	return t.try(ctx, fn, nil, s, nil)
+}
+
+/*
+	try makes the attempts for Try, TryContext, and Resume. Exactly one of
+	fn and plain is non-nil. Try passes its Operation as plain so that no
+	context needs to be built for attempts when nothing can observe it,
+	which keeps a call that succeeds first time free of heap allocations.
+	If sum isn't nil it is filled in before try returns.
+*/
+func (t *Tryer) try(ctx context.Context, fn OperationCtx, plain Operation, s State, sum *Summary) (errs []error, err error) {
+
+	c := t.config.Load()
+
+	if plain != nil && (len(c.opts.Middleware) > 0 || c.opts.BeforeAttempt != nil ||
+		c.opts.SetupAttempt != nil || c.opts.TeardownAttempt != nil) {
+		op := plain
+		fn = func(context.Context) error {
+			return op()
+		}
+		plain = nil
+	}
+
+	if c.opts.IdempotencyKey != nil {
+		ctx = withIdempotencyKey(ctx, c.opts.IdempotencyKey())
+	}
+
+	op := fn
+	for i := len(c.opts.Middleware) - 1; i >= 0; i-- {
+		fn = c.opts.Middleware[i](fn)
+	}
+	before := c.opts.BeforeAttempt
+	setup, teardown := c.opts.SetupAttempt, c.opts.TeardownAttempt
+
+	var backoff Backoff
+	if c.opts.Backoff != nil {
+		backoff = c.opts.Backoff()
+	}
+
+	total := s.Waited
+	start := time.Now().Add(-s.Elapsed)
+
+	var history History
+	fail := func(reason, cause error) ([]error, error) {
+		if c.opts.OnExhausted != nil && (reason == ErrTimeout || reason == ErrMaxRetries) {
+			if op == nil {
+				op = func(context.Context) error {
+					return plain()
+				}
+			}
+			c.opts.OnExhausted(op, errs)
+		}
+		e := &Error{
+			reason:   reason,
+			cause:    cause,
+			attempts: s.Attempts + len(errs),
+			elapsed:  time.Since(start),
+			history:  history,
+		}
+		if len(errs) > 0 {
+			e.last = errs[len(errs)-1]
+		}
+		if sum != nil {
+			*sum = Summary{
+				Attempts: e.attempts,
+				Elapsed:  e.elapsed,
+				Waited:   total,
+				Err:      e,
+			}
+		}
+		if n := c.opts.RecentOutcomes; n > 0 {
+			t.recent.add(n, Outcome{
+				Time:      time.Now(),
+				Operation: OperationFromContext(ctx),
+				Attempts:  e.attempts,
+				Elapsed:   e.elapsed,
+				Err:       e,
+			})
+		}
+		return errs, e
+	}
+
 	/*
-		We avoid using the current time as a seed because multiple
-		goroutines may be calling fn simultaneously. If they have
-		the same seed their jitter will not distribute those calls,
-		which is the purpose of jitter to begin with.
+		One timer is reused for every wait in a call and then returned to
+		timerPool for other calls. It has always fired and been drained
+		before it is reset or pooled. A timer stopped early by ctx might
+		still deliver a value so it is dropped instead.
 	*/
-	t.seedMu.Lock()
-	t.seed++
-	t.seedMu.Unlock()
-	r := rand.New(rand.NewSource(t.seed))
+	var timer *time.Timer
+	defer func() {
+		if timer != nil {
+			timerPool.Put(timer)
+		}
+	}()
+	wait := func(d time.Duration) bool {
+		if timer != nil {
+			timer.Reset(d)
+		} else if pooled, ok := timerPool.Get().(*time.Timer); ok {
+			timer = pooled
+			timer.Reset(d)
+		} else {
+			timer = time.NewTimer(d)
+		}
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			timer = nil
+			return false
+		case <-timer.C:
+			return true
+		}
+	}
 
-	var total time.Duration
+	if s.NextDelay > 0 {
+		total += s.NextDelay
+		if !wait(s.NextDelay) {
+			return fail(ctx.Err(), context.Cause(ctx))
+		}
+	}
 
-	for attempt := 0; attempt <= t.retries; attempt++ {
+	var call *attemptCtx
+	var uncounted int
+	for attempt := s.Attempts; ; attempt++ {
+
+		// Pick up any changes made by Update.
+		c = t.config.Load()
+
+		if ctx.Err() != nil {
+			return fail(ctx.Err(), context.Cause(ctx))
+		}
+
+		var err error
+		began := time.Now()
+		if plain != nil {
+			err = plain()
+		} else {
+			a := withAttempt(ctx, attemptInfo{
+				n:       attempt + 1,
+				max:     c.retries + 1,
+				elapsed: time.Since(start),
+			}, call)
+			call = a.call
+			var actx context.Context = a
+			if before != nil {
+				actx = before(actx)
+			}
+			err = c.attempt(actx, fn, start, setup, teardown)
+		}
+		took := time.Since(began)
+		t.observe(took)
 
-		err := fn()
+		if a := c.opts.Adaptive; a != nil {
+			if err == nil {
+				a.success()
+			} else {
+				a.failure()
+			}
+		}
+		if th := c.opts.Throttle; th != nil && err == nil {
+			th.success()
+		}
+		if ss := c.opts.SlowStart; ss != nil {
+			ss.record(err)
+		}
 		if err == nil {
+			if c.opts.OnSuccess != nil {
+				c.opts.OnSuccess(State{
+					Attempts: attempt + 1,
+					Elapsed:  time.Since(start),
+					Waited:   total,
+					History:  history,
+				}, took)
+			}
+			if sum != nil {
+				*sum = Summary{
+					Attempts:  attempt + 1,
+					Elapsed:   time.Since(start),
+					Waited:    total,
+					Succeeded: true,
+				}
+			}
+			if n := c.opts.RecentOutcomes; n > 0 {
+				t.recent.add(n, Outcome{
+					Time:      time.Now(),
+					Operation: OperationFromContext(ctx),
+					Attempts:  attempt + 1,
+					Elapsed:   time.Since(start),
+				})
+			}
 			return errs, nil
 		}
+		err, permanent := unwrapPermanent(err)
+		if errs == nil {
+			errs = make([]error, 0, min(c.retries+1, maxErrsCap))
+			history = make(History, 0, cap(errs))
+		}
 		errs = append(errs, err)
+		history = append(history, Attempt{
+			N:        attempt + 1,
+			Start:    began,
+			Duration: took,
+			Err:      err,
+		})
+
+		if ctx.Err() != nil {
+			return fail(ctx.Err(), context.Cause(ctx))
+		}
+
+		timedOut := !c.opts.CheckAttemptTimeouts && errors.Is(err, context.DeadlineExceeded)
+		if permanent || !timedOut && t.retry != nil && !t.retry(err) {
+			return fail(ErrCancelled, nil)
+		}
+
+		// Only failures that would be retried cost a token, as in gRPC.
+		if th := c.opts.Throttle; th != nil {
+			th.failure()
+		}
+
+		if c.opts.GiveUp != nil && c.opts.GiveUp(attempt+1, time.Since(start)) {
+			return fail(ErrCancelled, nil)
+		}
+
+		if c.opts.MaxRepeats > 0 && repeats(errs) >= c.opts.MaxRepeats {
+			return fail(ErrRepeatedFailure, nil)
+		}
+
+		if c.opts.UncountedTimeouts && errors.Is(err, context.DeadlineExceeded) {
+			uncounted++
+		}
+		counted := attempt - uncounted
+
+		if counted >= c.retries {
+			return fail(ErrMaxRetries, nil)
+		}
+
+		if t.Disabled() {
+			return fail(ErrDisabled, nil)
+		}
+
+		burn := 1.0
+		if br := c.opts.BurnRate; br != nil {
+			if r := br(); r > 1 {
+				burn = r
+				if counted >= int(float64(c.retries)/burn) {
+					return fail(ErrThrottled, nil)
+				}
+			}
+		}
+
+		if th := c.opts.Throttle; th != nil && !th.AllowPriority(PriorityFromContext(ctx)) {
+			return fail(ErrThrottled, nil)
+		}
+
+		if ss := c.opts.SlowStart; ss != nil && !ss.Allow() {
+			return fail(ErrThrottled, nil)
+		}
 
-		if t.retry != nil && !t.retry(err) {
-			return errs, ErrCancelled
+		hint, hinted := hintFrom(err)
+		if hinted && hint.Shed && PriorityFromContext(ctx) <= hint.ShedPriority {
+			return fail(ErrThrottled, nil)
 		}
 
-		sleep := t.base * math.Pow(t.exponent, float64(attempt))
+		var sleep time.Duration
+		if backoff != nil {
+			d, ok := backoff.Next(err)
+			if !ok {
+				return fail(ErrMaxRetries, nil)
+			}
+			sleep = d
+		} else {
+			sleep = c.delay(attempt)
+		}
+		if burn > 1 {
+			sleep = toDuration(float64(sleep) * burn)
+		}
+		if hinted {
+			sleep = max(sleep, hint.Delay)
+		}
+		if m := c.opts.Maintenance; m != nil {
+			if end := m(time.Now()); !end.IsZero() {
+				if !c.opts.WaitOutMaintenance {
+					return fail(ErrMaintenance, nil)
+				}
+				sleep = max(sleep, time.Until(end))
+			}
+		}
+		if h := c.opts.Health; h != nil && !c.opts.WaitForHealth && !h(ctx) {
+			return fail(ErrUnhealthy, nil)
+		}
+		if co := c.opts.Coordinator; co != nil {
+			if d, err := co.Coordinate(ctx, sleep); err == nil {
+				sleep = d
+			}
+		}
 
-		sleep = math.Min(t.maxInterval, sleep)
+		if c.outlasts(start, sleep, t.AttemptDuration()) {
+			return fail(ErrTimeout, nil)
+		}
 
-		sleep *= (1 - (r.Float64() * t.jitter))
+		// Checked last so retries stopped for other reasons don't use
+		// up the window.
+		if c.opts.MaxRetriesPerWindow > 0 && !t.window.allow(c.opts.MaxRetriesPerWindow, c.opts.Window) {
+			return fail(ErrThrottled, nil)
+		}
 
-		total += time.Duration(sleep)
-		if total > t.maxWait {
-			return errs, ErrTimeout
+		if c.opts.OnRetry != nil {
+			c.opts.OnRetry(State{
+				Attempts:  attempt + 1,
+				Elapsed:   time.Since(start),
+				Waited:    total,
+				NextDelay: sleep,
+				History:   slices.Clone(history),
+			}, err)
 		}
 
-		time.Sleep(time.Nanosecond * time.Duration(sleep))
+		total += sleep
+		waitStart := time.Now()
+		waited := wait(sleep)
+		history[len(history)-1].Delay = time.Since(waitStart)
+		if !waited {
+			return fail(ctx.Err(), context.Cause(ctx))
+		}
+
+		// Keep waiting without making attempts while the
+		// dependency is down.
+		for n := attempt + 1; c.opts.WaitForHealth && c.opts.Health != nil && !c.opts.Health(ctx); n++ {
+			if backoff != nil {
+				d, ok := backoff.Next(err)
+				if !ok {
+					return fail(ErrMaxRetries, nil)
+				}
+				sleep = d
+			} else {
+				sleep = c.delay(n)
+			}
+			if c.outlasts(start, sleep, t.AttemptDuration()) {
+				return fail(ErrTimeout, nil)
+			}
+			total += sleep
+			waited := wait(sleep)
+			history[len(history)-1].Delay = time.Since(waitStart)
+			if !waited {
+				return fail(ctx.Err(), context.Cause(ctx))
+			}
+		}
 	}
+}
 
-	return errs, ErrMaxRetries
+/*
+	attempt calls fn once with ctx, surrounded by setup and teardown from
+	Options.SetupAttempt and TeardownAttempt and limited by
+	Options.AttemptTimeout. The call to Try began at start.
+*/
+func (c *config) attempt(ctx context.Context, fn OperationCtx, start time.Time,
+	setup func(context.Context) (context.Context, error), teardown func(context.Context, error)) (err error) {
+
+	if setup != nil {
+		if ctx, err = setup(ctx); err != nil {
+			return err
+		}
+	}
+	if teardown != nil {
+		result := errAttemptPanicked
+		defer func() {
+			teardown(ctx, result)
+		}()
+		err = c.call(ctx, fn, start)
+		result = err
+		return err
+	}
+
+	return c.call(ctx, fn, start)
+}
+
+func (c *config) call(ctx context.Context, fn OperationCtx, start time.Time) error {
+	if c.opts.AttemptTimeout > 0 {
+		timeout := c.opts.AttemptTimeout
+		if c.maxWait > 0 {
+			left := c.maxWait - time.Since(start)
+			if left <= 0 {
+				return context.DeadlineExceeded
+			}
+			timeout = min(timeout, left)
+		}
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		return fn(ctx)
+	}
+	return fn(ctx)
+}
+
+/*
+	errAttemptPanicked is passed to Options.TeardownAttempt when the
+	operation panics.
+*/
+var errAttemptPanicked = errors.New("attempt panicked")
+
+/*
+	timerPool holds timers that have fired and been drained, so calls that
+	wait between attempts don't each allocate a new timer.
+*/
+var timerPool sync.Pool
+
+/*
+	maxErrsCap limits the capacity allocated up front for the errors of a
+	call, so a large Options.Retries doesn't allocate a large slice for a
+	call that fails only once or twice.
+*/
+const maxErrsCap = 8
+
+/*
+	ewmaWeight is the weight given to each new attempt duration in the
+	moving average reported by AttemptDuration.
+*/
+const ewmaWeight = 0.2
+
+/*
+	AttemptDuration returns an exponentially weighted moving average of
+	how long attempts made by t take, successful or not. It is 0 until the
+	first attempt completes. Try uses it to avoid retrying when an attempt
+	could not complete before Options.MaxWait.
+*/
+func (t *Tryer) AttemptDuration() time.Duration {
+	return time.Duration(t.ewma.Load())
+}
+
+func (t *Tryer) observe(d time.Duration) {
+	for {
+		old := t.ewma.Load()
+		avg := int64(d)
+		if old != 0 {
+			avg = int64(ewmaWeight*float64(d) + (1-ewmaWeight)*float64(old))
+		}
+		if t.ewma.CompareAndSwap(old, avg) {
+			return
+		}
+	}
+}
+
+/*
+	Delay returns a delay Try could wait after the given retry, counting
+	from 0 for the wait after the initial attempt. The delay grows by
+	Options.Exponent for each retry, is capped at Options.MaxInterval, is
+	jittered by Options.Jitter, and is at least Options.MinInterval.
+	Retries, MaxWait, and Options.Backoff are not considered.
+
+	Delay is useful for code that needs the backoff of a Tryer without
+	calling Try, such as supervising a long-lived connection.
+*/
+func (t *Tryer) Delay(retry int) time.Duration {
+	return t.config.Load().delay(retry)
+}
+
+/*
+	Schedule returns the delays Try would wait before each of the first n
+	retries, ignoring Options.Jitter and treating Options.MaxExponent as
+	though it were Options.Exponent. It previews the shape of the backoff,
+	for example to check a policy against a latency budget before using it.
+*/
+func (t *Tryer) Schedule(n int) []time.Duration {
+	c := t.config.Load()
+	s := make([]time.Duration, n)
+	for retry := range s {
+		s[retry] = toDuration(math.Max(c.minInterval, c.interval(retry, c.exponent)))
+	}
+	return s
+}
+
+func (c *config) delay(retry int) time.Duration {
+
+	r := c.rand
+
+	exponent := c.exponent
+	if c.opts.MaxExponent > 0 {
+		exponent += r.Float64() * (c.opts.MaxExponent - c.exponent)
+	}
+
+	sleep := c.interval(retry, exponent)
+
+	if retry > 0 || !c.opts.SkipFirstJitter {
+		sleep *= (1 - (r.Float64() * c.jitter))
+	}
+
+	sleep = math.Max(c.minInterval, sleep)
+
+	return toDuration(sleep)
+}
+
+/*
+	interval returns the delay before the given retry without jitter,
+	capped at maxInterval. Large retries and exponents make the power
+	overflow to +Inf, which the cap takes care of, except that a base of
+	0 then gives NaN.
+*/
+func (c *config) interval(retry int, exponent float64) float64 {
+
+	base := c.base
+	if a := c.opts.Adaptive; a != nil {
+		base += float64(a.Extra())
+	}
+
+	sleep := base * math.Pow(exponent, float64(retry))
+	if math.IsNaN(sleep) {
+		return 0
+	}
+
+	return math.Min(c.maxInterval, sleep)
+}
+
+/*
+	outlasts reports whether waiting for sleep and then making an attempt
+	lasting attempt would run past MaxWait for a call that began at start.
+	It subtracts rather than adds so a sleep clamped to the longest
+	Duration can't overflow and slip under the limit.
+*/
+func (c *config) outlasts(start time.Time, sleep, attempt time.Duration) bool {
+	left := max(c.maxWait-time.Since(start)-attempt, 0)
+	return sleep > left
+}
+
+/*
+	toDuration converts d to a time.Duration, clamping it to the longest
+	Duration rather than letting the conversion overflow into a negative
+	sleep.
+*/
+func toDuration(d float64) time.Duration {
+	if d >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return time.Duration(d)
 }
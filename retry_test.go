@@ -1,7 +1,12 @@
 package retry
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"math"
+	"os"
 	"testing"
 	"time"
 )
@@ -51,6 +56,49 @@ func TestNew(t *testing.T) {
 			Jitter:      1.5,
 		}},
 
+		// Both Retries and MaxAttempts are set.
+		{true, nil, Options{
+			Retries:     3,
+			MaxAttempts: 4,
+			Base:        time.Millisecond * 30,
+			MaxInterval: time.Second * 1,
+			MaxWait:     time.Second * 2,
+			Exponent:    2,
+			Jitter:      0.5,
+		}},
+
+		// MaxExponent is less than Exponent.
+		{true, nil, Options{
+			Retries:     3,
+			Base:        time.Millisecond * 30,
+			MaxInterval: time.Second * 1,
+			MaxWait:     time.Second * 2,
+			Exponent:    2,
+			MaxExponent: 1.5,
+			Jitter:      0.5,
+		}},
+
+		// MinInterval is greater than Base.
+		{true, nil, Options{
+			Retries:     3,
+			Base:        time.Millisecond * 30,
+			MaxInterval: time.Second * 1,
+			MinInterval: time.Millisecond * 40,
+			MaxWait:     time.Second * 2,
+			Exponent:    2,
+			Jitter:      0.5,
+		}},
+
+		// Base is greater than MaxInterval.
+		{true, nil, Options{
+			Retries:     3,
+			Base:        time.Second * 2,
+			MaxInterval: time.Second * 1,
+			MaxWait:     time.Second * 2,
+			Exponent:    2,
+			Jitter:      0.5,
+		}},
+
 		/*
 		   Should not return errors.
 		*/
@@ -127,6 +175,18 @@ func TestTry(t *testing.T) {
 			},
 		},
 
+		// Retry is nil but fn marks its error as
+		// permanent so we cancel after one attempt.
+		{
+			ErrCancelled,
+			false,
+			2000,
+			nil,
+			func() error {
+				return Permanent(errors.New("test"))
+			},
+		},
+
 		// Try's fn always returns an error therefore
 		// we should hit the maximum allowed attempts.
 		{
@@ -204,7 +264,7 @@ func TestTry(t *testing.T) {
 			return
 		}
 
-		if errs, err := tryer.Try(c.fn); c.wantErrs && errs == nil || err != c.wantErr {
+		if errs, err := tryer.Try(c.fn); c.wantErrs && errs == nil || !errors.Is(err, c.wantErr) {
 			fn := "nil"
 			if c.fn != nil {
 				fn = "func() error"
@@ -221,3 +281,751 @@ func TestTry(t *testing.T) {
 		}
 	}
 }
+
+func TestTryContext(t *testing.T) {
+
+	errDisconnect := errors.New("client disconnected")
+
+	tryer, err := New(nil, Options{
+		Retries:     3,
+		Base:        time.Millisecond * 30,
+		MaxInterval: time.Second * 1,
+		MaxWait:     time.Second * 2,
+		Exponent:    2,
+		Jitter:      0.5,
+	})
+	if err != nil {
+		t.Fatal("Failed to initialise Tryer while testing method TryContext:\n    ", err.Error())
+	}
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	errs, err := tryer.TryContext(ctx, func(context.Context) error {
+		cancel(errDisconnect)
+		return errors.New("test")
+	})
+
+	if len(errs) != 1 {
+		t.Errorf("Tryer.TryContext returned %d errs, wanted 1", len(errs))
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("errors.Is(%v, context.Canceled) = false, wanted true", err)
+	}
+	if !errors.Is(err, errDisconnect) {
+		t.Errorf("errors.Is(%v, errDisconnect) = false, wanted true", err)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+
+	var calls []string
+	mw := func(name string) Middleware {
+		return func(next OperationCtx) OperationCtx {
+			return func(ctx context.Context) error {
+				calls = append(calls, name)
+				return next(ctx)
+			}
+		}
+	}
+
+	tryer, err := New(nil, Options{
+		Retries:     1,
+		Base:        time.Millisecond * 5,
+		MaxInterval: time.Millisecond * 20,
+		MaxWait:     time.Second * 1,
+		Exponent:    2,
+		Middleware:  []Middleware{mw("outer"), mw("inner")},
+	})
+	if err != nil {
+		t.Fatal("Failed to initialise Tryer while testing Middleware:\n    ", err.Error())
+	}
+
+	_, _ = tryer.Try(func() error {
+		calls = append(calls, "fn")
+		return errors.New("test")
+	})
+
+	want := "[outer inner fn outer inner fn]"
+	if got := fmt.Sprint(calls); got != want {
+		t.Errorf("Middleware call order = %s, wanted %s", got, want)
+	}
+}
+
+func TestOnExhausted(t *testing.T) {
+
+	cases := []struct {
+		wantErr   error
+		wantCalls int
+		retry     Retry
+	}{
+		// Retries run out so OnExhausted is called.
+		{ErrMaxRetries, 1, nil},
+
+		// Cancelled operations aren't exhausted.
+		{ErrCancelled, 0, func(error) bool { return false }},
+	}
+
+	for _, c := range cases {
+
+		calls := 0
+		var gotErrs []error
+
+		tryer, err := New(c.retry, Options{
+			Retries:     2,
+			Base:        time.Millisecond * 5,
+			MaxInterval: time.Millisecond * 20,
+			MaxWait:     time.Second * 1,
+			Exponent:    2,
+			OnExhausted: func(fn OperationCtx, errs []error) {
+				calls++
+				gotErrs = errs
+			},
+		})
+		if err != nil {
+			t.Fatal("Failed to initialise Tryer while testing OnExhausted:\n    ", err.Error())
+		}
+
+		errs, err := tryer.Try(func() error {
+			return errors.New("test")
+		})
+
+		if !errors.Is(err, c.wantErr) || calls != c.wantCalls {
+			t.Errorf("Tryer.Try returned %v and called OnExhausted %d times, wanted %v and %d",
+				err, calls, c.wantErr, c.wantCalls)
+		}
+		if calls == 1 && len(gotErrs) != len(errs) {
+			t.Errorf("OnExhausted received %d errs, wanted %d", len(gotErrs), len(errs))
+		}
+	}
+}
+
+func TestAttemptDuration(t *testing.T) {
+
+	tryer, err := New(nil, Options{
+		Retries:     3,
+		Base:        time.Millisecond * 5,
+		MaxInterval: time.Millisecond * 20,
+		MaxWait:     time.Millisecond * 50,
+		Exponent:    2,
+	})
+	if err != nil {
+		t.Fatal("Failed to initialise Tryer while testing AttemptDuration:\n    ", err.Error())
+	}
+
+	// Each attempt takes 30ms so after the first there
+	// isn't time left in MaxWait to wait and try again.
+	errs, err := tryer.Try(func() error {
+		time.Sleep(time.Millisecond * 30)
+		return errors.New("test")
+	})
+
+	if !errors.Is(err, ErrTimeout) || len(errs) != 1 {
+		t.Errorf("Tryer.Try returned %v after %d attempts, wanted %v after 1",
+			err, len(errs), ErrTimeout)
+	}
+	if d := tryer.AttemptDuration(); d < time.Millisecond*30 {
+		t.Errorf("Tryer.AttemptDuration() = %s, wanted at least 30ms", d)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+
+	o := Options{
+		Retries:     5,
+		Base:        time.Millisecond * 5,
+		MaxInterval: time.Millisecond * 20,
+		MaxWait:     time.Second * 1,
+		Exponent:    2,
+	}
+
+	tryer, err := New(nil, o)
+	if err != nil {
+		t.Fatal("Failed to initialise Tryer while testing method Update:\n    ", err.Error())
+	}
+
+	// Invalid Options leave the Tryer unchanged.
+	if err := tryer.Update(Options{Exponent: 0.5}); err == nil {
+		t.Error("Tryer.Update with Exponent 0.5 returned nil error, wanted error")
+	}
+
+	// Lowering Retries during a call takes effect on its next attempt.
+	errs, err := tryer.Try(func() error {
+		if err := tryer.Update(Options{
+			Retries:     1,
+			Base:        o.Base,
+			MaxInterval: o.MaxInterval,
+			MaxWait:     o.MaxWait,
+			Exponent:    o.Exponent,
+		}); err != nil {
+			t.Fatal("Tryer.Update returned error:\n    ", err.Error())
+		}
+		return errors.New("test")
+	})
+
+	if !errors.Is(err, ErrMaxRetries) || len(errs) != 2 {
+		t.Errorf("Tryer.Try after Update returned %v after %d attempts, wanted %v after 2",
+			err, len(errs), ErrMaxRetries)
+	}
+
+	// Unmarshalling config into the current Options keeps their hooks.
+	retries := 0
+	o.OnRetry = func(State, error) { retries++ }
+	if err := tryer.Update(o); err != nil {
+		t.Fatal("Tryer.Update returned error:\n    ", err.Error())
+	}
+	reload := tryer.Options()
+	if err := json.Unmarshal([]byte(`{"retries": 2}`), &reload); err != nil {
+		t.Fatal(err)
+	}
+	if err := tryer.Update(reload); err != nil {
+		t.Fatal("Tryer.Update returned error:\n    ", err.Error())
+	}
+	errs, err = tryer.Try(func() error {
+		return errors.New("test")
+	})
+	if !errors.Is(err, ErrMaxRetries) || len(errs) != 3 || retries != 2 {
+		t.Errorf("Tryer.Try after reloading config returned %v after %d attempts and %d calls of OnRetry, "+
+			"wanted %v after 3 and 2", err, len(errs), retries, ErrMaxRetries)
+	}
+}
+
+func TestMinInterval(t *testing.T) {
+
+	o := Options{
+		Retries:     3,
+		Base:        time.Millisecond * 20,
+		MaxInterval: time.Millisecond * 100,
+		MinInterval: time.Millisecond * 15,
+		MaxWait:     time.Second * 1,
+		Exponent:    2,
+		Jitter:      1,
+	}
+
+	tryer, err := New(nil, o)
+	if err != nil {
+		t.Fatal("Failed to initialise Tryer while testing MinInterval:\n    ", err.Error())
+	}
+
+	for retry := 0; retry < 100; retry++ {
+		if d := tryer.Delay(retry % 4); d < o.MinInterval || d > o.MaxInterval {
+			t.Fatalf("Delay(%d) = %s, wanted between %s and %s",
+				retry%4, d, o.MinInterval, o.MaxInterval)
+		}
+	}
+}
+
+func TestSkipFirstJitter(t *testing.T) {
+
+	o := Options{
+		Retries:         3,
+		Base:            time.Millisecond * 20,
+		MaxInterval:     time.Millisecond * 100,
+		MaxWait:         time.Second * 1,
+		Exponent:        2,
+		Jitter:          1,
+		SkipFirstJitter: true,
+	}
+
+	tryer, err := New(nil, o)
+	if err != nil {
+		t.Fatal("Failed to initialise Tryer while testing SkipFirstJitter:\n    ", err.Error())
+	}
+
+	jittered := false
+	for i := 0; i < 100; i++ {
+		if d := tryer.Delay(0); d != o.Base {
+			t.Fatalf("Delay(0) = %s, wanted %s", d, o.Base)
+		}
+		if tryer.Delay(1) != o.Base*2 {
+			jittered = true
+		}
+	}
+	if !jittered {
+		t.Errorf("Delay(1) was never jittered, wanted jitter after the first retry")
+	}
+}
+
+func TestMaxExponent(t *testing.T) {
+
+	o := Options{
+		Retries:     3,
+		Base:        time.Millisecond * 10,
+		MaxInterval: time.Second * 1,
+		MaxWait:     time.Second * 2,
+		Exponent:    1.5,
+		MaxExponent: 2.5,
+	}
+
+	tryer, err := New(nil, o)
+	if err != nil {
+		t.Fatal("Failed to initialise Tryer while testing MaxExponent:\n    ", err.Error())
+	}
+
+	lo, hi := time.Duration(22500000), time.Duration(62500000) // Base * 1.5² and Base * 2.5²
+	seen := map[time.Duration]bool{}
+	for i := 0; i < 100; i++ {
+		d := tryer.Delay(2)
+		if d < lo || d > hi {
+			t.Fatalf("Delay(2) = %s, wanted between %s and %s", d, lo, hi)
+		}
+		seen[d] = true
+	}
+	if len(seen) < 2 {
+		t.Errorf("Delay(2) always returned the same delay, wanted the exponent to vary")
+	}
+}
+
+func TestDelayOverflow(t *testing.T) {
+
+	cases := []struct {
+		o     Options
+		retry int
+		want  time.Duration
+	}{
+		{Options{Base: time.Second, MaxInterval: time.Minute, Exponent: 10}, 1000, time.Minute},
+		{Options{Base: time.Second, MaxInterval: math.MaxInt64, Exponent: 10}, 1000, math.MaxInt64},
+		{Options{Base: time.Second, MaxInterval: math.MaxInt64, Exponent: 2}, math.MaxInt, math.MaxInt64},
+		{Options{Base: 0, MaxInterval: time.Minute, Exponent: 10}, 1000, 0},
+	}
+
+	for _, c := range cases {
+
+		tryer, err := New(nil, c.o)
+		if err != nil {
+			t.Fatal("Failed to initialise Tryer while testing Delay:\n    ", err.Error())
+		}
+
+		if got := tryer.Delay(c.retry); got != c.want {
+			t.Errorf("Delay(%d) with %v = %s, wanted %s", c.retry, c.o, got, c.want)
+		}
+	}
+
+	tryer, err := New(nil, Options{
+		Base:        time.Millisecond * 10,
+		MaxInterval: time.Millisecond * 50,
+		Exponent:    2,
+		Jitter:      1,
+	})
+	if err != nil {
+		t.Fatal("Failed to initialise Tryer while testing Schedule:\n    ", err.Error())
+	}
+
+	want := []time.Duration{
+		time.Millisecond * 10,
+		time.Millisecond * 20,
+		time.Millisecond * 40,
+		time.Millisecond * 50,
+		time.Millisecond * 50,
+	}
+	if got := tryer.Schedule(len(want)); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("Schedule(%d) = %v, wanted %v", len(want), got, want)
+	}
+
+	// A delay clamped to the longest Duration still exceeds MaxWait.
+	tryer, err = New(nil, Options{
+		Retries:     3,
+		Base:        time.Millisecond * 1,
+		MaxInterval: math.MaxInt64,
+		MaxWait:     time.Second * 1,
+		Exponent:    1e300,
+	})
+	if err != nil {
+		t.Fatal("Failed to initialise Tryer while testing Delay:\n    ", err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*2)
+	defer cancel()
+	errs, err := tryer.TryContext(ctx, func(context.Context) error {
+		return errors.New("test")
+	})
+	if !errors.Is(err, ErrTimeout) || len(errs) != 2 {
+		t.Errorf("TryContext with a clamped delay returned %v after %d attempts, wanted %v after 2",
+			err, len(errs), ErrTimeout)
+	}
+}
+
+func TestAttemptTimeout(t *testing.T) {
+
+	tryer, err := New(nil, Options{
+		Retries:        5,
+		Base:           time.Millisecond * 1,
+		MaxInterval:    time.Millisecond * 1,
+		MaxWait:        time.Millisecond * 100,
+		Exponent:       1,
+		AttemptTimeout: time.Millisecond * 50,
+	})
+	if err != nil {
+		t.Fatal("Failed to initialise Tryer while testing AttemptTimeout:\n    ", err.Error())
+	}
+
+	// Attempts take 30ms so the third starts with less than 50ms of MaxWait left.
+	var timeouts []time.Duration
+	_, err = tryer.TryContext(context.Background(), func(ctx context.Context) error {
+		deadline, ok := ctx.Deadline()
+		if !ok {
+			t.Fatal("Attempt context has no deadline")
+		}
+		timeouts = append(timeouts, time.Until(deadline))
+		time.Sleep(time.Millisecond * 30)
+		return errors.New("test")
+	})
+
+	if err == nil || len(timeouts) < 3 {
+		t.Fatalf("TryContext returned %v after %d attempts, wanted an error after at least 3", err, len(timeouts))
+	}
+	if first := timeouts[0]; first > time.Millisecond*50 || first < time.Millisecond*45 {
+		t.Errorf("First attempt had %s to run, wanted about 50ms", first)
+	}
+	if last := timeouts[len(timeouts)-1]; last >= time.Millisecond*45 {
+		t.Errorf("Last attempt had %s to run, wanted only what was left of MaxWait", last)
+	}
+}
+
+func TestAttemptTimeoutNoMaxWait(t *testing.T) {
+
+	tryer, err := New(nil, Options{
+		Exponent:       1,
+		AttemptTimeout: time.Millisecond * 50,
+	})
+	if err != nil {
+		t.Fatal("Failed to initialise Tryer while testing AttemptTimeout:\n    ", err.Error())
+	}
+
+	// Without MaxWait the attempt gets the whole AttemptTimeout.
+	var left time.Duration
+	_, err = tryer.TryContext(context.Background(), func(ctx context.Context) error {
+		deadline, _ := ctx.Deadline()
+		left = time.Until(deadline)
+		return ctx.Err()
+	})
+	if err != nil || left < time.Millisecond*45 {
+		t.Errorf("TryContext returned %v with %s left to run, wanted nil with about 50ms", err, left)
+	}
+
+	tryer, err = New(nil, Options{
+		Exponent:       1,
+		MaxWait:        time.Second,
+		AttemptTimeout: time.Millisecond * 50,
+	})
+	if err != nil {
+		t.Fatal("Failed to initialise Tryer while testing AttemptTimeout:\n    ", err.Error())
+	}
+
+	// An attempt isn't made once MaxWait is used up.
+	calls := 0
+	errs, err := tryer.Resume(context.Background(), State{Elapsed: time.Second * 2},
+		func(ctx context.Context) error {
+			calls++
+			return nil
+		})
+	if err == nil || calls != 0 || len(errs) != 1 || !errors.Is(errs[0], context.DeadlineExceeded) {
+		t.Errorf("Resume after MaxWait returned %v, %v after %d calls, wanted an error and no calls", errs, err, calls)
+	}
+}
+
+func TestGiveUp(t *testing.T) {
+
+	var got []int
+	tryer, err := New(nil, Options{
+		Retries:     5,
+		Base:        time.Millisecond * 1,
+		MaxInterval: time.Millisecond * 5,
+		MaxWait:     time.Second * 1,
+		Exponent:    2,
+		GiveUp: func(attempts int, elapsed time.Duration) bool {
+			got = append(got, attempts)
+			return attempts == 3
+		},
+	})
+	if err != nil {
+		t.Fatal("Failed to initialise Tryer while testing GiveUp:\n    ", err.Error())
+	}
+
+	errs, err := tryer.Try(func() error {
+		return errors.New("test")
+	})
+	if !errors.Is(err, ErrCancelled) || len(errs) != 3 {
+		t.Errorf("Try returned %v after %d attempts, wanted %v after 3", err, len(errs), ErrCancelled)
+	}
+	if fmt.Sprint(got) != "[1 2 3]" {
+		t.Errorf("GiveUp was called with attempts %v, wanted [1 2 3]", got)
+	}
+}
+
+func TestMaxAttempts(t *testing.T) {
+
+	for _, n := range []int{1, 2, 5} {
+
+		tryer, err := New(nil, Options{
+			MaxAttempts: n,
+			Base:        time.Millisecond * 1,
+			MaxInterval: time.Millisecond * 5,
+			MaxWait:     time.Second * 1,
+			Exponent:    2,
+		})
+		if err != nil {
+			t.Fatal("Failed to initialise Tryer while testing MaxAttempts:\n    ", err.Error())
+		}
+
+		var max int
+		errs, err := tryer.TryContext(context.Background(), func(ctx context.Context) error {
+			max, _ = MaxAttemptsFromContext(ctx)
+			return errors.New("test")
+		})
+		if !errors.Is(err, ErrMaxRetries) || len(errs) != n || max != n {
+			t.Errorf("TryContext with MaxAttempts %d returned %v after %d attempts of %d, wanted %v after %d of %d",
+				n, err, len(errs), max, ErrMaxRetries, n, n)
+		}
+	}
+}
+
+func TestUncountedTimeouts(t *testing.T) {
+
+	cases := []struct {
+		uncounted bool
+		wantCalls int
+	}{
+		{false, 2},
+		{true, 4},
+	}
+
+	for _, c := range cases {
+
+		tryer, err := New(nil, Options{
+			MaxAttempts:       2,
+			Base:              time.Millisecond * 1,
+			MaxInterval:       time.Millisecond * 5,
+			MaxWait:           time.Second * 1,
+			Exponent:          2,
+			AttemptTimeout:    time.Millisecond * 5,
+			UncountedTimeouts: c.uncounted,
+		})
+		if err != nil {
+			t.Fatal("Failed to initialise Tryer while testing UncountedTimeouts:\n    ", err.Error())
+		}
+
+		// The first two attempts time out, the rest fail outright.
+		calls := 0
+		_, err = tryer.TryContext(context.Background(), func(ctx context.Context) error {
+			calls++
+			if calls <= 2 {
+				<-ctx.Done()
+				return ctx.Err()
+			}
+			return errors.New("test")
+		})
+		if !errors.Is(err, ErrMaxRetries) || calls != c.wantCalls {
+			t.Errorf("TryContext with UncountedTimeouts %t returned %v after %d calls, wanted %v after %d",
+				c.uncounted, err, calls, ErrMaxRetries, c.wantCalls)
+		}
+	}
+}
+
+func TestCheckAttemptTimeouts(t *testing.T) {
+
+	cases := []struct {
+		check     bool
+		wantErr   error
+		wantCalls int
+	}{
+		{false, nil, 2},
+		{true, ErrCancelled, 1},
+	}
+
+	for _, c := range cases {
+
+		tryer, err := New(
+			func(error) bool { return false },
+			Options{
+				Retries:              3,
+				Base:                 time.Millisecond * 1,
+				MaxInterval:          time.Millisecond * 5,
+				MaxWait:              time.Second * 1,
+				Exponent:             2,
+				AttemptTimeout:       time.Millisecond * 5,
+				CheckAttemptTimeouts: c.check,
+			})
+		if err != nil {
+			t.Fatal("Failed to initialise Tryer while testing CheckAttemptTimeouts:\n    ", err.Error())
+		}
+
+		calls := 0
+		_, err = tryer.TryContext(context.Background(), func(ctx context.Context) error {
+			calls++
+			if calls == 1 {
+				<-ctx.Done()
+				return fmt.Errorf("query: %w", ctx.Err())
+			}
+			return nil
+		})
+		if !errors.Is(err, c.wantErr) || c.wantErr == nil && err != nil || calls != c.wantCalls {
+			t.Errorf("TryContext with CheckAttemptTimeouts %t returned %v after %d calls, wanted %v after %d",
+				c.check, err, calls, c.wantErr, c.wantCalls)
+		}
+	}
+}
+
+func TestOnSuccess(t *testing.T) {
+
+	var got State
+	var took time.Duration
+	tryer, err := New(nil, Options{
+		Retries:     3,
+		Base:        time.Millisecond * 5,
+		MaxInterval: time.Millisecond * 5,
+		MaxWait:     time.Second * 1,
+		Exponent:    1,
+		OnSuccess: func(s State, d time.Duration) {
+			got, took = s, d
+		},
+	})
+	if err != nil {
+		t.Fatal("Failed to initialise Tryer while testing OnSuccess:\n    ", err.Error())
+	}
+
+	attempts := 0
+	tryer.Try(func() error {
+		attempts++
+		if attempts < 3 {
+			return errors.New("test")
+		}
+		time.Sleep(time.Millisecond * 2)
+		return nil
+	})
+
+	if got.Attempts != 3 || len(got.History) != 2 || got.Waited != time.Millisecond*10 {
+		t.Errorf("OnSuccess received %+v, wanted 3 attempts with 2 in History and 10ms waited", got)
+	}
+	if took < time.Millisecond*2 || got.Elapsed < got.Waited+took {
+		t.Errorf("OnSuccess received took %s and elapsed %s, wanted at least 2ms and %s",
+			took, got.Elapsed, got.Waited+took)
+	}
+}
+
+func TestMustNew(t *testing.T) {
+
+	valid := Options{
+		Base:        time.Millisecond,
+		MaxInterval: time.Millisecond,
+		MaxWait:     time.Second,
+		Exponent:    1,
+	}
+	if MustNew(nil, valid) == nil {
+		t.Error("MustNew with valid Options returned nil")
+	}
+
+	cases := []struct {
+		name string
+		fn   func()
+	}{
+		{"MustNew", func() { MustNew(nil, Options{Jitter: 2}) }},
+		{"Must", func() { Must(New(nil, Options{Jitter: 2})) }},
+	}
+
+	for _, c := range cases {
+		func() {
+			defer func() {
+				if _, ok := recover().(*OptionsError); !ok {
+					t.Errorf("%s with invalid Options didn't panic with an *OptionsError", c.name)
+				}
+			}()
+			c.fn()
+		}()
+	}
+}
+
+type dirKey struct{}
+
+func TestSetupAttempt(t *testing.T) {
+
+	var made, removed []string
+	setupFails := 1
+
+	tryer, err := New(nil, Options{
+		Retries:     3,
+		Base:        time.Millisecond * 1,
+		MaxInterval: time.Millisecond * 5,
+		MaxWait:     time.Second * 1,
+		Exponent:    2,
+		SetupAttempt: func(ctx context.Context) (context.Context, error) {
+			if setupFails > 0 {
+				setupFails--
+				return nil, errors.New("setup")
+			}
+			dir, err := os.MkdirTemp(t.TempDir(), "attempt")
+			if err != nil {
+				return nil, err
+			}
+			made = append(made, dir)
+			return context.WithValue(ctx, dirKey{}, dir), nil
+		},
+		TeardownAttempt: func(ctx context.Context, err error) {
+			dir := ctx.Value(dirKey{}).(string)
+			os.RemoveAll(dir)
+			removed = append(removed, dir)
+		},
+	})
+	if err != nil {
+		t.Fatal("Failed to initialise Tryer while testing SetupAttempt:\n    ", err.Error())
+	}
+
+	calls := 0
+	errs, err := tryer.Try(func() error {
+		calls++
+		if calls < 3 {
+			return errors.New("test")
+		}
+		return nil
+	})
+
+	// The failed setup counts as an attempt without calling fn.
+	if err != nil || len(errs) != 3 || calls != 3 {
+		t.Fatalf("Try returned %v with %d errors after %d calls, wanted nil with 3 after 3", err, len(errs), calls)
+	}
+	if len(made) != 3 || len(removed) != 3 {
+		t.Fatalf("SetupAttempt made %d directories and TeardownAttempt removed %d, wanted 3 and 3", len(made), len(removed))
+	}
+	seen := map[string]bool{}
+	for i, dir := range made {
+		if seen[dir] || removed[i] != dir {
+			t.Errorf("attempt %d used directory %s and removed %s, wanted a fresh one removed", i+2, dir, removed[i])
+		}
+		seen[dir] = true
+		if _, err := os.Stat(dir); !os.IsNotExist(err) {
+			t.Errorf("directory %s still exists after its attempt", dir)
+		}
+	}
+}
+
+func TestTeardownAttemptPanic(t *testing.T) {
+
+	var got error
+	torn := false
+	tryer, err := New(nil, Options{
+		Retries:     1,
+		Base:        time.Millisecond * 1,
+		MaxInterval: time.Millisecond * 5,
+		MaxWait:     time.Second * 1,
+		Exponent:    2,
+		TeardownAttempt: func(ctx context.Context, err error) {
+			torn, got = true, err
+		},
+	})
+	if err != nil {
+		t.Fatal("Failed to initialise Tryer while testing TeardownAttempt:\n    ", err.Error())
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("panic in operation didn't reach the caller")
+			}
+		}()
+		tryer.Try(func() error {
+			panic("test")
+		})
+	}()
+
+	if !torn || got == nil {
+		t.Errorf("TeardownAttempt called = %t with error %v after a panic, wanted true with a non-nil error", torn, got)
+	}
+}
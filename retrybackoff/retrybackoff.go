@@ -0,0 +1,107 @@
+/*
+Package retrybackoff lets code written for github.com/cenkalti/backoff
+and retry.Tryer be used together, so a migration can happen one call site
+at a time.
+
+The BackOff interface here has the same method set as the one in
+cenkalti/backoff, so values satisfy both without either package importing
+the other:
+
+	// A cenkalti/backoff policy driving a Tryer.
+	t, err := retry.New(shouldRetry, retry.Options{
+		Retries: 10,
+		MaxWait: time.Minute,
+		Backoff: retrybackoff.From(func() retrybackoff.BackOff {
+			return backoff.NewExponentialBackOff()
+		}),
+	})
+
+	// A Tryer's policy driving cenkalti/backoff.
+	err = backoff.Retry(op, retrybackoff.FromTryer(t))
+*/
+package retrybackoff
+
+import (
+	"time"
+
+	"github.com/jakebowkett/retry"
+)
+
+/*
+	BackOff has the method set of the BackOff interface in
+	github.com/cenkalti/backoff.
+*/
+type BackOff interface {
+	NextBackOff() time.Duration
+	Reset()
+}
+
+/*
+	Stop is returned by NextBackOff to indicate no more retries should be
+	made. It has the same value as backoff.Stop.
+*/
+const Stop time.Duration = -1
+
+/*
+	From adapts a BackOff for use as retry.Options.Backoff. The newBackOff
+	function is called for each call to Try, since a BackOff holds the
+	state of a single sequence of retries. A delay of Stop ends the retries.
+*/
+func From(newBackOff func() BackOff) func() retry.Backoff {
+	return func() retry.Backoff {
+		b := newBackOff()
+		b.Reset()
+		return adapter{b}
+	}
+}
+
+type adapter struct {
+	b BackOff
+}
+
+func (a adapter) Next(error) (time.Duration, bool) {
+	d := a.b.NextBackOff()
+	if d == Stop {
+		return 0, false
+	}
+	return d, true
+}
+
+/*
+	FromTryer returns a BackOff producing the delays of t, for use with
+	functions such as backoff.Retry. It returns Stop once the retries
+	allowed by t's Options.RetryLimit have been used, or once the delays
+	would exceed its Options.MaxWait. The BackOff is not safe for
+	concurrent use.
+*/
+func FromTryer(t *retry.Tryer) BackOff {
+	return &tryerBackOff{t: t}
+}
+
+type tryerBackOff struct {
+	t     *retry.Tryer
+	retry int
+	total time.Duration
+}
+
+func (b *tryerBackOff) NextBackOff() time.Duration {
+
+	o := b.t.Options()
+	if b.retry >= o.RetryLimit() {
+		return Stop
+	}
+
+	d := b.t.Delay(b.retry)
+	if b.total+d > o.MaxWait {
+		return Stop
+	}
+
+	b.retry++
+	b.total += d
+	return d
+}
+
+func (b *tryerBackOff) Reset() {
+	b.retry = 0
+	b.total = 0
+}
@@ -0,0 +1,83 @@
+package retrybackoff
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jakebowkett/retry"
+)
+
+/*
+	constant mimics backoff.ConstantBackOff combined with
+	backoff.WithMaxRetries.
+*/
+type constant struct {
+	interval time.Duration
+	max      int
+	n        int
+}
+
+func (c *constant) NextBackOff() time.Duration {
+	if c.n >= c.max {
+		return Stop
+	}
+	c.n++
+	return c.interval
+}
+
+func (c *constant) Reset() { c.n = 0 }
+
+func TestFrom(t *testing.T) {
+
+	tryer, err := retry.New(nil, retry.Options{
+		Retries: 10,
+		MaxWait: time.Second,
+		Backoff: From(func() BackOff {
+			return &constant{interval: time.Millisecond, max: 2}
+		}),
+	})
+	if err != nil {
+		t.Fatal("Failed to initialise Tryer:\n    ", err.Error())
+	}
+
+	for i := 0; i < 2; i++ {
+		errs, err := tryer.Try(func() error {
+			return errors.New("test")
+		})
+		if !errors.Is(err, retry.ErrMaxRetries) || len(errs) != 3 {
+			t.Errorf("Tryer.Try returned %v after %d attempts, wanted %v after 3",
+				err, len(errs), retry.ErrMaxRetries)
+		}
+	}
+}
+
+func TestFromTryer(t *testing.T) {
+
+	for _, o := range []retry.Options{
+		{Retries: 3},
+		{MaxAttempts: 4},
+	} {
+		o.Base = time.Millisecond * 10
+		o.MaxInterval = time.Second
+		o.MaxWait = time.Second
+		o.Exponent = 2
+
+		tryer, err := retry.New(nil, o)
+		if err != nil {
+			t.Fatal("Failed to initialise Tryer:\n    ", err.Error())
+		}
+
+		b := FromTryer(tryer)
+		for round := 0; round < 2; round++ {
+			var got []time.Duration
+			for d := b.NextBackOff(); d != Stop; d = b.NextBackOff() {
+				got = append(got, d)
+			}
+			if len(got) != 3 || got[0] != time.Millisecond*10 || got[2] != time.Millisecond*40 {
+				t.Errorf("FromTryer with %s delays = %v, wanted [10ms 20ms 40ms]", o, got)
+			}
+			b.Reset()
+		}
+	}
+}
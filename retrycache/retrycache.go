@@ -0,0 +1,135 @@
+/*
+Package retrycache classifies the transient errors returned by Redis and
+memcached clients and provides Options with short delays suited to
+caches, where a slow retry is often worse than a miss.
+
+	t, err := retry.New(retrycache.Transient, retrycache.Fast())
+
+It matches errors by the replies servers send, such as "LOADING" or
+"READONLY", and by the net.Error interface, so it does not depend on any
+particular client.
+*/
+package retrycache
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/jakebowkett/retry"
+	"github.com/jakebowkett/retry/retrynet"
+)
+
+/*
+	transientReplies are the prefixes of Redis error replies that indicate
+	a temporary condition, such as a replica loading its dataset, a primary
+	demoted during failover, or a cluster slot that has moved.
+*/
+var transientReplies = []string{
+	"LOADING ",
+	"READONLY ",
+	"MASTERDOWN ",
+	"CLUSTERDOWN ",
+	"TRYAGAIN ",
+	"MOVED ",
+	"ASK ",
+	"BUSY ",
+}
+
+/*
+	transientMessages are parts of the messages memcached clients use for
+	temporary failures.
+*/
+var transientMessages = []string{
+	"i/o timeout",
+	"connect timeout",
+	"SERVER_ERROR out of memory",
+	"SERVER_ERROR temporary failure",
+	"no servers configured or available",
+}
+
+/*
+	Redirect reports whether err is a Redis Cluster MOVED or ASK reply,
+	meaning the key's slot is served by another node. Cluster aware
+	clients normally follow these themselves; one that reaches the caller
+	usually means the client's slot map was stale, and retrying after it
+	has been refreshed succeeds.
+*/
+func Redirect(err error) bool {
+	return reply(err, "MOVED ") || reply(err, "ASK ")
+}
+
+/*
+	Transient reports whether err is likely to go away if the operation
+	is tried again: Redis replies such as LOADING, READONLY, TRYAGAIN,
+	CLUSTERDOWN and Redirects, temporary memcached failures, and network
+	errors for which retrynet.Transient returns true, such as i/o
+	timeouts. It is suitable for passing to retry.New.
+*/
+func Transient(err error) bool {
+
+	if err == nil {
+		return false
+	}
+
+	for _, p := range transientReplies {
+		if reply(err, p) {
+			return true
+		}
+	}
+
+	msg := err.Error()
+	for _, m := range transientMessages {
+		if strings.Contains(msg, m) {
+			return true
+		}
+	}
+
+	return retrynet.Transient(err)
+}
+
+/*
+	reply reports whether err, or any error it wraps, has a message
+	starting with prefix. Redis clients return error replies as errors
+	whose message is the reply, which callers often wrap.
+*/
+func reply(err error, prefix string) bool {
+	for ; err != nil; err = errors.Unwrap(err) {
+		if strings.HasPrefix(err.Error(), prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+	Fast returns Options for cache reads and writes on a request path. A
+	few retries a few milliseconds apart ride out dropped connections and
+	redirects, and MaxWait gives up well before a caller would notice.
+*/
+func Fast() retry.Options {
+	return retry.Options{
+		Retries:     3,
+		Base:        time.Millisecond * 2,
+		MaxInterval: time.Millisecond * 20,
+		MaxWait:     time.Millisecond * 100,
+		Exponent:    2,
+		Jitter:      0.5,
+	}
+}
+
+/*
+	Failover returns Options for cache operations that must succeed and
+	can wait out a failover, during which a demoted primary replies
+	READONLY and a restarted replica replies LOADING for several seconds.
+*/
+func Failover() retry.Options {
+	return retry.Options{
+		Retries:     10,
+		Base:        time.Millisecond * 50,
+		MaxInterval: time.Second * 1,
+		MaxWait:     time.Second * 10,
+		Exponent:    2,
+		Jitter:      0.5,
+	}
+}
@@ -0,0 +1,70 @@
+package retrycache
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/jakebowkett/retry"
+)
+
+/*
+	redisError mimics the error type Redis clients use for error replies.
+*/
+type redisError string
+
+func (e redisError) Error() string { return string(e) }
+
+func TestTransient(t *testing.T) {
+
+	timeout := &net.OpError{Op: "read", Net: "tcp", Err: &timeoutError{}}
+
+	cases := []struct {
+		err           error
+		wantTransient bool
+		wantRedirect  bool
+	}{
+		{nil, false, false},
+		{errors.New("test"), false, false},
+		{redisError("WRONGTYPE Operation against a key holding the wrong kind of value"), false, false},
+		{redisError("LOADING Redis is loading the dataset in memory"), true, false},
+		{fmt.Errorf("set: %w", redisError("READONLY You can't write against a read only replica.")), true, false},
+		{redisError("MOVED 3999 127.0.0.1:6381"), true, true},
+		{fmt.Errorf("get: %w", redisError("ASK 3999 127.0.0.1:6381")), true, true},
+		{errors.New("memcache: SERVER_ERROR out of memory storing object"), true, false},
+		{timeout, true, false},
+	}
+
+	for _, c := range cases {
+		if got := Transient(c.err); got != c.wantTransient {
+			t.Errorf("Transient(%v) = %t, wanted %t", c.err, got, c.wantTransient)
+		}
+		if got := Redirect(c.err); got != c.wantRedirect {
+			t.Errorf("Redirect(%v) = %t, wanted %t", c.err, got, c.wantRedirect)
+		}
+	}
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestPresets(t *testing.T) {
+
+	presets := []struct {
+		name string
+		o    retry.Options
+	}{
+		{"Fast", Fast()},
+		{"Failover", Failover()},
+	}
+
+	for _, p := range presets {
+		if _, err := retry.New(Transient, p.o); err != nil {
+			t.Errorf("%s returned invalid Options: %s", p.name, err)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+/*
+Package retrychaos injects faults into operations run by a retry.Tryer,
+so teams can see how their systems behave under heavy retrying without
+breaking the real dependencies. Add its Middleware to the Options of a
+Tryer in a test or staging environment:
+
+	chaos, err := retrychaos.New(retrychaos.Config{
+		FailureRate: 0.3,
+		Latency:     time.Millisecond * 200,
+		LatencyRate: 0.1,
+	})
+	if err != nil {
+		log.Fatalln(err)
+	}
+	t, err := retry.New(shouldRetry, retry.Options{
+		// ...
+		Middleware: []retry.Middleware{chaos},
+	})
+*/
+package retrychaos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/jakebowkett/retry"
+)
+
+/*
+	ErrInjected is the error returned by attempts that are failed on
+	purpose, unless Config.Err is set.
+*/
+var ErrInjected = errors.New("retrychaos: injected failure")
+
+/*
+	Config describes the faults to inject.
+*/
+type Config struct {
+	/*
+		FailureRate is a value between 0 and 1 giving the fraction of
+		attempts that fail without calling the operation.
+	*/
+	FailureRate float64
+
+	/*
+		Err is the error returned by failed attempts. It defaults to
+		ErrInjected.
+	*/
+	Err error
+
+	/*
+		Latency is the extra delay added before an attempt, chosen for a
+		fraction LatencyRate of attempts. The delay ends early if the
+		context of the attempt is done.
+	*/
+	Latency time.Duration
+
+	/*
+		LatencyRate is a value between 0 and 1 giving the fraction of
+		attempts that are delayed by Latency.
+	*/
+	LatencyRate float64
+}
+
+/*
+	New returns a Middleware that injects the faults described by c. It
+	returns an error if either rate in c is outside the range 0 to 1 or
+	Latency is negative.
+*/
+func New(c Config) (retry.Middleware, error) {
+
+	if c.FailureRate < 0 || c.FailureRate > 1 {
+		return nil, fmt.Errorf("expected a .FailureRate value between 0 and 1, got %.2f", c.FailureRate)
+	}
+	if c.LatencyRate < 0 || c.LatencyRate > 1 {
+		return nil, fmt.Errorf("expected a .LatencyRate value between 0 and 1, got %.2f", c.LatencyRate)
+	}
+	if c.Latency < 0 {
+		return nil, fmt.Errorf("expected .Latency to be 0 or greater, got %s", c.Latency)
+	}
+	if c.Err == nil {
+		c.Err = ErrInjected
+	}
+
+	return func(next retry.OperationCtx) retry.OperationCtx {
+		return func(ctx context.Context) error {
+
+			if c.Latency > 0 && rand.Float64() < c.LatencyRate {
+				timer := time.NewTimer(c.Latency)
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return ctx.Err()
+				case <-timer.C:
+				}
+			}
+
+			if rand.Float64() < c.FailureRate {
+				return c.Err
+			}
+
+			return next(ctx)
+		}
+	}, nil
+}
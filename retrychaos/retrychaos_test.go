@@ -0,0 +1,65 @@
+package retrychaos
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNew(t *testing.T) {
+
+	cases := []struct {
+		c       Config
+		wantErr bool
+	}{
+		{Config{FailureRate: -0.1}, true},
+		{Config{FailureRate: 1.1}, true},
+		{Config{LatencyRate: 2}, true},
+		{Config{Latency: -time.Second}, true},
+		{Config{FailureRate: 0.5, Latency: time.Millisecond, LatencyRate: 0.5}, false},
+	}
+
+	for _, c := range cases {
+		if _, err := New(c.c); c.wantErr != (err != nil) {
+			t.Errorf("New(%+v) returned error %v, wanted error: %t", c.c, err, c.wantErr)
+		}
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+
+	errTest := errors.New("test")
+
+	cases := []struct {
+		c         Config
+		wantErr   error
+		wantCalls int
+		minTime   time.Duration
+	}{
+		{Config{}, nil, 1, 0},
+		{Config{FailureRate: 1}, ErrInjected, 0, 0},
+		{Config{FailureRate: 1, Err: errTest}, errTest, 0, 0},
+		{Config{Latency: time.Millisecond * 10, LatencyRate: 1}, nil, 1, time.Millisecond * 10},
+	}
+
+	for _, c := range cases {
+
+		m, err := New(c.c)
+		if err != nil {
+			t.Fatal("Failed to initialise Middleware:\n    ", err.Error())
+		}
+
+		calls := 0
+		start := time.Now()
+		err = m(func(context.Context) error {
+			calls++
+			return nil
+		})(context.Background())
+
+		if err != c.wantErr || calls != c.wantCalls || time.Since(start) < c.minTime {
+			t.Errorf("Middleware with %+v returned %v after %d calls in %s, wanted %v after %d in at least %s",
+				c.c, err, calls, time.Since(start), c.wantErr, c.wantCalls, c.minTime)
+		}
+	}
+}
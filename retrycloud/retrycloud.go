@@ -0,0 +1,163 @@
+/*
+Package retrycloud classifies the throttling errors returned by cloud
+service APIs and backs off from them with long, decorrelated delays. It
+recognises errors from the AWS SDKs and other smithy based clients by the
+methods they implement, so it does not depend on any SDK.
+
+	t, err := retry.New(retrycloud.Retryable, retry.Options{
+		Retries: 8,
+		MaxWait: time.Minute,
+		Backoff: retrycloud.Backoff(time.Millisecond*50, time.Second, time.Second*20),
+	})
+*/
+package retrycloud
+
+import (
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"time"
+
+	"github.com/jakebowkett/retry"
+)
+
+/*
+	The interfaces below are implemented by errors from smithy based
+	clients, such as AWS SDK for Go v2, and from AWS SDK for Go v1.
+*/
+type (
+	errorCoder   interface{ ErrorCode() string }
+	coder        interface{ Code() string }
+	httpStatuser interface{ HTTPStatusCode() int }
+	statusCoder  interface{ StatusCode() int }
+)
+
+/*
+	throttlingCodes are error codes services use to report throttling.
+*/
+var throttlingCodes = map[string]bool{
+	"Throttling":                             true,
+	"ThrottlingException":                    true,
+	"ThrottledException":                     true,
+	"RequestThrottledException":              true,
+	"TooManyRequestsException":               true,
+	"ProvisionedThroughputExceededException": true,
+	"TransactionInProgressException":         true,
+	"RequestLimitExceeded":                   true,
+	"BandwidthLimitExceeded":                 true,
+	"LimitExceededException":                 true,
+	"RequestThrottled":                       true,
+	"SlowDown":                               true,
+	"PriorRequestNotComplete":                true,
+	"EC2ThrottledException":                  true,
+}
+
+/*
+	transientCodes are error codes for failures unrelated to throttling
+	that are worth retrying.
+*/
+var transientCodes = map[string]bool{
+	"RequestTimeout":          true,
+	"RequestTimeoutException": true,
+	"InternalError":           true,
+	"InternalFailure":         true,
+	"ServiceUnavailable":      true,
+}
+
+func code(err error) string {
+	var ec errorCoder
+	if errors.As(err, &ec) {
+		return ec.ErrorCode()
+	}
+	var c coder
+	if errors.As(err, &c) {
+		return c.Code()
+	}
+	return ""
+}
+
+func status(err error) int {
+	var hs httpStatuser
+	if errors.As(err, &hs) {
+		return hs.HTTPStatusCode()
+	}
+	var sc statusCoder
+	if errors.As(err, &sc) {
+		return sc.StatusCode()
+	}
+	return 0
+}
+
+/*
+	Throttling reports whether err indicates the caller is being throttled,
+	either by one of the error codes cloud services use for throttling, such
+	as "Throttling", "SlowDown", or "RequestLimitExceeded", or by an HTTP
+	status of 429 Too Many Requests or 503 Service Unavailable.
+*/
+func Throttling(err error) bool {
+	if err == nil {
+		return false
+	}
+	if throttlingCodes[code(err)] {
+		return true
+	}
+	s := status(err)
+	return s == http.StatusTooManyRequests || s == http.StatusServiceUnavailable
+}
+
+/*
+	Retryable reports whether err is worth retrying: throttling errors,
+	errors with codes for transient service failures, and server errors
+	with a 5xx HTTP status. It is suitable for passing to retry.New.
+*/
+func Retryable(err error) bool {
+	if Throttling(err) || transientCodes[code(err)] {
+		return true
+	}
+	s := status(err)
+	return s >= 500 && s <= 599 && s != http.StatusNotImplemented
+}
+
+/*
+	Backoff returns a function for retry.Options.Backoff that uses
+	decorrelated jitter, where each delay is random between base and three
+	times the previous delay, capped at max. This spreads out retries from
+	many clients better than exponential backoff with jitter.
+
+	After a throttling error the delay is at least throttleBase instead of
+	base, so that throttled calls back off much further than calls that
+	failed for other reasons.
+*/
+func Backoff(base, throttleBase, max time.Duration) func() retry.Backoff {
+	return func() retry.Backoff {
+		return &decorrelated{base: base, throttleBase: throttleBase, max: max}
+	}
+}
+
+type decorrelated struct {
+	base         time.Duration
+	throttleBase time.Duration
+	max          time.Duration
+	prev         time.Duration
+}
+
+func (d *decorrelated) Next(err error) (time.Duration, bool) {
+
+	base := d.base
+	if Throttling(err) && d.throttleBase > base {
+		base = d.throttleBase
+	}
+
+	upper := d.prev * 3
+	if upper <= base {
+		upper = base + 1
+	}
+
+	delay := base + time.Duration(rand.Int64N(int64(upper-base)))
+	if delay > d.max {
+		delay = d.max
+	}
+
+	d.prev = delay
+	return delay, true
+}
@@ -0,0 +1,86 @@
+package retrycloud
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+/*
+	apiError mimics smithy.GenericAPIError.
+*/
+type apiError struct{ code string }
+
+func (e *apiError) Error() string     { return e.code }
+func (e *apiError) ErrorCode() string { return e.code }
+
+/*
+	responseError mimics awshttp.ResponseError.
+*/
+type responseError struct{ status int }
+
+func (e *responseError) Error() string       { return fmt.Sprint(e.status) }
+func (e *responseError) HTTPStatusCode() int { return e.status }
+
+func TestClassify(t *testing.T) {
+
+	cases := []struct {
+		err            error
+		wantThrottling bool
+		wantRetryable  bool
+	}{
+		{errors.New("test"), false, false},
+		{&apiError{"AccessDenied"}, false, false},
+		{&apiError{"SlowDown"}, true, true},
+		{fmt.Errorf("operation error: %w", &apiError{"ThrottlingException"}), true, true},
+		{&apiError{"InternalError"}, false, true},
+		{&responseError{429}, true, true},
+		{&responseError{503}, true, true},
+		{&responseError{500}, false, true},
+		{&responseError{501}, false, false},
+		{&responseError{404}, false, false},
+	}
+
+	for _, c := range cases {
+		if got := Throttling(c.err); got != c.wantThrottling {
+			t.Errorf("Throttling(%v) = %t, wanted %t", c.err, got, c.wantThrottling)
+		}
+		if got := Retryable(c.err); got != c.wantRetryable {
+			t.Errorf("Retryable(%v) = %t, wanted %t", c.err, got, c.wantRetryable)
+		}
+	}
+}
+
+func TestBackoff(t *testing.T) {
+
+	base := time.Millisecond * 10
+	throttleBase := time.Millisecond * 100
+	max := time.Millisecond * 500
+
+	b := Backoff(base, throttleBase, max)()
+	prev := time.Duration(0)
+
+	for i := 0; i < 20; i++ {
+
+		err := errors.New("test")
+		min := base
+		if i%2 == 1 {
+			err = &apiError{"Throttling"}
+			min = throttleBase
+		}
+
+		d, ok := b.Next(err)
+		upper := prev * 3
+		if upper < min {
+			upper = min + 1
+		}
+		if upper > max {
+			upper = max
+		}
+		if !ok || d < min || d > upper {
+			t.Errorf("Backoff.Next(%v) = %s, wanted between %s and %s", err, d, min, upper)
+		}
+		prev = d
+	}
+}
@@ -0,0 +1,108 @@
+/*
+Package retrydebug serves the state of the Tryers in a retry.Registry as
+JSON for quick operational inspection, in the manner of expvar and
+net/http/pprof. Unlike those packages it doesn't register a handler
+itself:
+
+	http.Handle("/debug/retry", retrydebug.Handler(retry.DefaultRegistry))
+
+For each Tryer the response shows its Options, the average duration of
+its attempts, the state of its Throttle and Adaptive if it has them, and
+its recent outcomes if Options.RecentOutcomes is set.
+*/
+package retrydebug
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/jakebowkett/retry"
+)
+
+type tryerJSON struct {
+	Options         retry.Options `json:"options"`
+	AttemptDuration string        `json:"attemptDuration"`
+	ThrottleTokens  *float64      `json:"throttleTokens,omitempty"`
+	AdaptiveExtra   string        `json:"adaptiveExtra,omitempty"`
+	Recent          []outcomeJSON `json:"recent"`
+	Failures        int           `json:"recentFailures"`
+}
+
+type outcomeJSON struct {
+	Time      time.Time `json:"time"`
+	Operation string    `json:"operation,omitempty"`
+	Attempts  int       `json:"attempts"`
+	Elapsed   string    `json:"elapsed"`
+	Err       string    `json:"error,omitempty"`
+}
+
+/*
+	Handler returns an http.Handler that responds with a JSON object
+	describing each Tryer in r, keyed by the name it is registered under.
+	A query such as ?name=db limits the response to one Tryer. If r is nil
+	retry.DefaultRegistry is used.
+*/
+func Handler(r *retry.Registry) http.Handler {
+
+	if r == nil {
+		r = retry.DefaultRegistry
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+
+		tryers := r.Snapshot()
+		if name := req.URL.Query().Get("name"); name != "" {
+			t, ok := tryers[name]
+			if !ok {
+				http.Error(w, "no Tryer is registered under "+name, http.StatusNotFound)
+				return
+			}
+			tryers = map[string]*retry.Tryer{name: t}
+		}
+
+		out := make(map[string]tryerJSON, len(tryers))
+		for name, t := range tryers {
+			out[name] = describe(t)
+		}
+
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "\t")
+		enc.Encode(out)
+	})
+}
+
+func describe(t *retry.Tryer) tryerJSON {
+
+	o := t.Options()
+	j := tryerJSON{
+		Options:         o,
+		AttemptDuration: t.AttemptDuration().String(),
+		Recent:          []outcomeJSON{},
+	}
+
+	if th := o.Throttle; th != nil {
+		tokens := th.Tokens()
+		j.ThrottleTokens = &tokens
+	}
+	if a := o.Adaptive; a != nil {
+		j.AdaptiveExtra = a.Extra().String()
+	}
+
+	for _, oc := range t.Recent() {
+		r := outcomeJSON{
+			Time:      oc.Time,
+			Operation: oc.Operation,
+			Attempts:  oc.Attempts,
+			Elapsed:   oc.Elapsed.String(),
+		}
+		if oc.Err != nil {
+			r.Err = oc.Err.Error()
+			j.Failures++
+		}
+		j.Recent = append(j.Recent, r)
+	}
+
+	return j
+}
@@ -0,0 +1,93 @@
+package retrydebug
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jakebowkett/retry"
+)
+
+func TestHandler(t *testing.T) {
+
+	th, err := retry.NewThrottle(10, 0.1)
+	if err != nil {
+		t.Fatal("Failed to initialise Throttle:\n    ", err.Error())
+	}
+
+	db, err := retry.New(nil, retry.Options{
+		Retries:        1,
+		Base:           time.Millisecond,
+		MaxInterval:    time.Millisecond * 5,
+		MaxWait:        time.Second * 1,
+		Exponent:       2,
+		Throttle:       th,
+		RecentOutcomes: 10,
+	})
+	if err != nil {
+		t.Fatal("Failed to initialise Tryer:\n    ", err.Error())
+	}
+	cache, err := retry.New(nil, retry.Options{
+		Base:        time.Millisecond,
+		MaxInterval: time.Millisecond,
+		MaxWait:     time.Second,
+		Exponent:    1,
+	})
+	if err != nil {
+		t.Fatal("Failed to initialise Tryer:\n    ", err.Error())
+	}
+
+	r := retry.NewRegistry()
+	r.Register("db", db)
+	r.Register("cache", cache)
+
+	ctx := retry.WithOperation(context.Background(), "GetUser")
+	db.TryContext(ctx, func(ctx context.Context) error { return nil })
+	db.TryContext(ctx, func(ctx context.Context) error { return errors.New("connection refused") })
+
+	h := Handler(r)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/retry", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Handler responded with status %d, wanted %d", rec.Code, http.StatusOK)
+	}
+
+	var got map[string]struct {
+		Options        retry.Options `json:"options"`
+		ThrottleTokens *float64      `json:"throttleTokens"`
+		Recent         []struct {
+			Operation string `json:"operation"`
+			Attempts  int    `json:"attempts"`
+			Err       string `json:"error"`
+		} `json:"recent"`
+		Failures int `json:"recentFailures"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("Handler responded with invalid JSON: %s\n%s", err, rec.Body)
+	}
+
+	if len(got) != 2 {
+		t.Errorf("Handler described %d Tryers, wanted 2", len(got))
+	}
+	d := got["db"]
+	if d.Options.Retries != 1 || d.ThrottleTokens == nil || *d.ThrottleTokens >= 10 {
+		t.Errorf("Handler described db as %+v, wanted 1 retry and fewer than 10 throttle tokens", d)
+	}
+	if len(d.Recent) != 2 || d.Failures != 1 || d.Recent[1].Err == "" || d.Recent[1].Operation != "GetUser" {
+		t.Errorf("Handler described db's recent outcomes as %+v, wanted a success then a failure of GetUser", d.Recent)
+	}
+	if c := got["cache"]; c.ThrottleTokens != nil || len(c.Recent) != 0 {
+		t.Errorf("Handler described cache as %+v, wanted no throttle or outcomes", c)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/retry?name=missing", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Handler responded to an unknown name with status %d, wanted %d", rec.Code, http.StatusNotFound)
+	}
+}
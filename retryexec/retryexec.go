@@ -0,0 +1,85 @@
+/*
+Package retryexec helps retry commands run with os/exec.
+
+	t, err := retry.New(retryexec.ExitCodes([]int{retryexec.TempFail}, nil), opts)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	err = retryexec.RunCmd(ctx, t, func(ctx context.Context) *exec.Cmd {
+		return exec.CommandContext(ctx, "rsync", "-a", src, dst)
+	})
+*/
+package retryexec
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+
+	"github.com/jakebowkett/retry"
+)
+
+/*
+	TempFail is the exit code sysexits.h defines as EX_TEMPFAIL, used by
+	programs to report a temporary failure that may succeed if retried.
+*/
+const TempFail = 75
+
+/*
+	ExitCodes returns a retry.Retry classifying errors by the exit code of
+	an *exec.ExitError. Exit codes in abortOn are never retried. Otherwise
+	exit codes in retryOn are retried, or every exit code if retryOn is
+	empty. Errors that aren't from a command exiting, such as the command
+	not being found, are never retried.
+*/
+func ExitCodes(retryOn, abortOn []int) retry.Retry {
+	return func(err error) bool {
+		code, ok := ExitCode(err)
+		if !ok || contains(abortOn, code) {
+			return false
+		}
+		return len(retryOn) == 0 || contains(retryOn, code)
+	}
+}
+
+/*
+	ExitCode returns the exit code of the *exec.ExitError in err's chain.
+	If err is a *retry.Error the error from its last attempt is used. The
+	ok result is false if there is no *exec.ExitError.
+*/
+func ExitCode(err error) (code int, ok bool) {
+
+	var re *retry.Error
+	if errors.As(err, &re) {
+		err = re.LastError()
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode(), true
+	}
+	return 0, false
+}
+
+/*
+	RunCmd runs the command returned by newCmd, retrying it with t until it
+	succeeds or t gives up. Since an exec.Cmd can only be run once, newCmd
+	is called to create a fresh one for every attempt; it should pass ctx
+	to exec.CommandContext so the command is killed if ctx is done.
+*/
+func RunCmd(ctx context.Context, t *retry.Tryer, newCmd func(ctx context.Context) *exec.Cmd) error {
+	_, err := t.TryContext(ctx, func(ctx context.Context) error {
+		return newCmd(ctx).Run()
+	})
+	return err
+}
+
+func contains(codes []int, code int) bool {
+	for _, c := range codes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+package retryexec
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/jakebowkett/retry"
+)
+
+func TestRunCmd(t *testing.T) {
+
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	cases := []struct {
+		exit      int
+		wantCalls int
+		wantErr   error
+	}{
+		// Temporary failures are retried.
+		{TempFail, 3, retry.ErrMaxRetries},
+
+		// Usage errors are aborted.
+		{2, 1, retry.ErrCancelled},
+
+		// Other codes aren't in the retry set.
+		{1, 1, retry.ErrCancelled},
+
+		{0, 1, nil},
+	}
+
+	for _, c := range cases {
+
+		tryer, err := retry.New(ExitCodes([]int{TempFail, 2}, []int{2}), retry.Options{
+			Retries:     2,
+			Base:        time.Millisecond,
+			MaxInterval: time.Millisecond * 5,
+			MaxWait:     time.Second * 5,
+			Exponent:    2,
+		})
+		if err != nil {
+			t.Fatal("Failed to initialise Tryer:\n    ", err.Error())
+		}
+
+		calls := 0
+		err = RunCmd(context.Background(), tryer, func(ctx context.Context) *exec.Cmd {
+			calls++
+			return exec.CommandContext(ctx, "sh", "-c", "exit "+strconv.Itoa(c.exit))
+		})
+
+		if !errors.Is(err, c.wantErr) || calls != c.wantCalls {
+			t.Errorf("RunCmd exiting %d returned %v after %d calls, wanted %v after %d",
+				c.exit, err, calls, c.wantErr, c.wantCalls)
+		}
+		if code, ok := ExitCode(err); c.exit != 0 && (!ok || code != c.exit) {
+			t.Errorf("ExitCode(%v) = %d, %t, wanted %d, true", err, code, ok, c.exit)
+		}
+	}
+}
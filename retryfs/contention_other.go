@@ -0,0 +1,8 @@
+//go:build !unix && !windows
+
+package retryfs
+
+/*
+	Contention isn't recognised on other operating systems.
+*/
+var contention []error
@@ -0,0 +1,13 @@
+//go:build unix
+
+package retryfs
+
+import "syscall"
+
+var contention = []error{
+	syscall.EAGAIN,
+	syscall.EWOULDBLOCK,
+	syscall.EBUSY,
+	syscall.ETXTBSY,
+	syscall.EINTR,
+}
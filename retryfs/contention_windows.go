@@ -0,0 +1,16 @@
+//go:build windows
+
+package retryfs
+
+import "syscall"
+
+const (
+	errorSharingViolation syscall.Errno = 32
+	errorLockViolation    syscall.Errno = 33
+)
+
+var contention = []error{
+	errorSharingViolation,
+	errorLockViolation,
+	syscall.ERROR_ACCESS_DENIED,
+}
@@ -0,0 +1,93 @@
+/*
+Package retryfs retries filesystem operations that fail because another
+process is briefly using a file, such as a lock held by another process
+or, on Windows, a virus scanner or indexer holding a file open while it
+is renamed or removed.
+
+	if err := retryfs.Rename(ctx, t, tmp, path); err != nil {
+		return err
+	}
+*/
+package retryfs
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/jakebowkett/retry"
+)
+
+/*
+	ShortOptions returns a policy suited to filesystem contention, which
+	usually clears within milliseconds: many quick retries over at most a
+	couple of seconds.
+*/
+func ShortOptions() retry.Options {
+	return retry.Options{
+		Retries:     20,
+		Base:        time.Millisecond,
+		MaxInterval: time.Millisecond * 100,
+		MaxWait:     time.Second * 2,
+		Exponent:    2,
+		Jitter:      0.5,
+	}
+}
+
+/*
+	Contention reports whether err is a filesystem error caused by another
+	process using the same file or lock, which is likely to clear if the
+	operation is tried again shortly. Which errors count depends on the
+	operating system:
+
+		Unix     EAGAIN, EWOULDBLOCK, EBUSY, ETXTBSY, EINTR
+		Windows  ERROR_SHARING_VIOLATION, ERROR_LOCK_VIOLATION, ERROR_ACCESS_DENIED
+
+	ERROR_ACCESS_DENIED is included on Windows because it is what removing
+	or renaming a file that another process has open usually reports.
+*/
+func Contention(err error) bool {
+	if err == nil {
+		return false
+	}
+	for _, errno := range contention {
+		if errors.Is(err, errno) {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+	Do calls fn, retrying it with t while it fails with errors for which
+	Contention returns true. Other errors stop the retries immediately.
+*/
+func Do(ctx context.Context, t *retry.Tryer, fn func() error) error {
+	_, err := t.TryContext(ctx, func(context.Context) error {
+		err := fn()
+		if err != nil && !Contention(err) {
+			return retry.Permanent(err)
+		}
+		return err
+	})
+	return err
+}
+
+/*
+	Rename is os.Rename retried with Do.
+*/
+func Rename(ctx context.Context, t *retry.Tryer, oldpath, newpath string) error {
+	return Do(ctx, t, func() error {
+		return os.Rename(oldpath, newpath)
+	})
+}
+
+/*
+	Remove is os.Remove retried with Do.
+*/
+func Remove(ctx context.Context, t *retry.Tryer, name string) error {
+	return Do(ctx, t, func() error {
+		return os.Remove(name)
+	})
+}
@@ -0,0 +1,80 @@
+package retryfs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jakebowkett/retry"
+)
+
+func TestDo(t *testing.T) {
+
+	if len(contention) == 0 {
+		t.Skip("contention errors aren't recognised on this OS")
+	}
+
+	o := ShortOptions()
+	o.Retries = 3
+	tryer, err := retry.New(nil, o)
+	if err != nil {
+		t.Fatal("Failed to initialise Tryer:\n    ", err.Error())
+	}
+
+	busy := &fs.PathError{Op: "rename", Path: "file", Err: contention[0]}
+
+	cases := []struct {
+		errs      []error // returned by successive calls
+		wantErr   error
+		wantCalls int
+	}{
+		{[]error{busy, busy, nil}, nil, 3},
+		{[]error{busy, fs.ErrNotExist}, retry.ErrCancelled, 2},
+		{[]error{busy, busy, busy, busy}, retry.ErrMaxRetries, 4},
+	}
+
+	for _, c := range cases {
+		calls := 0
+		err := Do(context.Background(), tryer, func() error {
+			calls++
+			return c.errs[calls-1]
+		})
+		if !errors.Is(err, c.wantErr) || c.wantErr == nil && err != nil || calls != c.wantCalls {
+			t.Errorf("Do with errors %v returned %v after %d calls, wanted %v after %d",
+				c.errs, err, calls, c.wantErr, c.wantCalls)
+		}
+	}
+}
+
+func TestRename(t *testing.T) {
+
+	dir := t.TempDir()
+	oldpath := filepath.Join(dir, "old")
+	newpath := filepath.Join(dir, "new")
+	if err := os.WriteFile(oldpath, []byte("test"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tryer, err := retry.New(nil, ShortOptions())
+	if err != nil {
+		t.Fatal("Failed to initialise Tryer:\n    ", err.Error())
+	}
+
+	if err := Rename(context.Background(), tryer, oldpath, newpath); err != nil {
+		t.Errorf("Rename returned %v, wanted nil", err)
+	}
+
+	// The file no longer exists which isn't contention.
+	err = Remove(context.Background(), tryer, oldpath)
+	if !errors.Is(err, retry.ErrCancelled) {
+		t.Errorf("Remove of missing file returned %v, wanted %v", err, retry.ErrCancelled)
+	}
+
+	if Contention(fmt.Errorf("wrapped: %w", fs.ErrPermission)) {
+		t.Error("Contention(fs.ErrPermission) = true, wanted false")
+	}
+}
@@ -0,0 +1,220 @@
+package retryio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/jakebowkett/retry"
+)
+
+/*
+	ErrBudgetExhausted is returned by Multipart.Transfer when a part fails
+	after the retries shared by all parts have been used up.
+*/
+var ErrBudgetExhausted = errors.New("retryio: retry budget for parts exhausted")
+
+/*
+	Part is one contiguous range of a multipart transfer.
+*/
+type Part struct {
+	N      int   // Index of the part, from 0.
+	Offset int64 // Offset of the part's first byte in the whole object.
+	Size   int64 // Length of the part in bytes.
+}
+
+/*
+	PartFunc transfers the bytes of p starting done bytes into it, for
+	example by uploading them with a part number or downloading them with
+	an HTTP Range request. It returns the number of bytes it transferred,
+	including when it fails part way, so that a retry resumes from there.
+*/
+type PartFunc = func(ctx context.Context, p Part, done int64) (n int64, err error)
+
+/*
+	Multipart transfers a large object as independently retried parts,
+	such as an S3 multipart upload or a ranged download. A failed part is
+	retried on its own rather than restarting the whole transfer.
+
+	Each part is tried with its own call to the Tryer, so Options.Retries
+	limits the retries of any one part. The budget passed to NewMultipart
+	additionally limits retries across all parts, so that a transfer with
+	many parts against a failing store gives up rather than retrying each
+	part in turn.
+*/
+type Multipart struct {
+	t        *retry.Tryer
+	size     int64
+	partSize int64
+	mu       sync.Mutex
+	budget   int
+	done     []int64
+}
+
+/*
+	NewMultipart returns a Multipart that splits size bytes into parts of
+	partSize bytes, the last of which may be shorter, retrying them with t
+	and allowing budget retries in total. A budget less than 0 means there
+	is no limit beyond that of t.
+*/
+func NewMultipart(t *retry.Tryer, size, partSize int64, budget int) (*Multipart, error) {
+
+	if size < 0 {
+		return nil, fmt.Errorf("expected size to be at least 0, got %d", size)
+	}
+	if partSize <= 0 {
+		return nil, fmt.Errorf("expected partSize to be greater than 0, got %d", partSize)
+	}
+
+	n := (size + partSize - 1) / partSize
+	return &Multipart{
+		t:        t,
+		size:     size,
+		partSize: partSize,
+		budget:   budget,
+		done:     make([]int64, n),
+	}, nil
+}
+
+/*
+	Parts returns the parts the transfer is split into.
+*/
+func (m *Multipart) Parts() []Part {
+	parts := make([]Part, len(m.done))
+	for i := range parts {
+		parts[i] = m.part(i)
+	}
+	return parts
+}
+
+func (m *Multipart) part(i int) Part {
+	off := int64(i) * m.partSize
+	return Part{N: i, Offset: off, Size: min(m.partSize, m.size-off)}
+}
+
+/*
+	Done returns the number of bytes of part n transferred so far.
+*/
+func (m *Multipart) Done(n int) int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.done[n]
+}
+
+/*
+	Transferred returns the number of bytes transferred so far across all
+	parts.
+*/
+func (m *Multipart) Transferred() int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var total int64
+	for _, d := range m.done {
+		total += d
+	}
+	return total
+}
+
+/*
+	Transfer calls fn for each part not yet complete, running up to
+	concurrency parts at once. It returns once every part is complete or
+	one part has failed for good, in which case the parts still running
+	are cancelled and the error names the failed part.
+
+	Progress is kept between calls, so calling Transfer again after an
+	error resumes each part from the bytes it had already transferred.
+*/
+func (m *Multipart) Transfer(ctx context.Context, concurrency int, fn PartFunc) error {
+
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg    sync.WaitGroup
+		once  sync.Once
+		first error
+		slots = make(chan struct{}, concurrency)
+	)
+
+	for i := range m.done {
+
+		p := m.part(i)
+		if m.Done(i) >= p.Size {
+			continue
+		}
+
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			if err := m.transfer(ctx, p, fn); err != nil {
+				once.Do(func() {
+					first = err
+					cancel()
+				})
+			}
+		}()
+	}
+
+	wg.Wait()
+	if first != nil {
+		return first
+	}
+	return ctx.Err()
+}
+
+func (m *Multipart) transfer(ctx context.Context, p Part, fn PartFunc) error {
+
+	_, err := m.t.TryContext(ctx, func(ctx context.Context) error {
+
+		if n, _ := retry.AttemptFromContext(ctx); n > 1 && !m.spend() {
+			return retry.Permanent(ErrBudgetExhausted)
+		}
+
+		done := m.Done(p.N)
+		n, err := fn(ctx, p, done)
+		m.mu.Lock()
+		m.done[p.N] = min(done+n, p.Size)
+		m.mu.Unlock()
+		return err
+	})
+
+	var re *retry.Error
+	if errors.As(err, &re) && re.LastError() == ErrBudgetExhausted {
+		err = ErrBudgetExhausted
+	}
+	if err != nil {
+		return fmt.Errorf("retryio: part %d: %w", p.N, err)
+	}
+	return nil
+}
+
+/*
+	spend takes one retry from the shared budget, reporting false if none
+	are left.
+*/
+func (m *Multipart) spend() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.budget < 0 {
+		return true
+	}
+	if m.budget == 0 {
+		return false
+	}
+	m.budget--
+	return true
+}
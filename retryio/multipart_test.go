@@ -0,0 +1,122 @@
+package retryio
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jakebowkett/retry"
+)
+
+func TestMultipart(t *testing.T) {
+
+	cases := []struct {
+		name     string
+		size     int64
+		partSize int64
+		budget   int
+		failures map[int]int // failed attempts by part
+		wantErr  error
+	}{
+		{"no failures", 100, 30, 0, nil, nil},
+		{"within budget", 100, 30, 3, map[int]int{0: 1, 3: 2}, nil},
+		{"unlimited budget", 100, 30, -1, map[int]int{0: 2, 1: 2, 2: 2}, nil},
+		{"budget exhausted", 100, 30, 1, map[int]int{1: 1, 2: 1}, ErrBudgetExhausted},
+		{"part exhausted", 100, 30, -1, map[int]int{2: 4}, retry.ErrMaxRetries},
+	}
+
+	for _, c := range cases {
+
+		tryer, err := retry.New(nil, retry.Options{
+			Retries:     2,
+			Base:        time.Millisecond,
+			MaxInterval: time.Millisecond * 5,
+			MaxWait:     time.Second * 1,
+			Exponent:    2,
+		})
+		if err != nil {
+			t.Fatal("Failed to initialise Tryer:\n    ", err.Error())
+		}
+
+		m, err := NewMultipart(tryer, c.size, c.partSize, c.budget)
+		if err != nil {
+			t.Fatal("Failed to initialise Multipart:\n    ", err.Error())
+		}
+
+		var mu sync.Mutex
+		failures := map[int]int{}
+		for k, v := range c.failures {
+			failures[k] = v
+		}
+
+		// Each failing attempt transfers half of what remains
+		// of the part, so retries must resume from done.
+		err = m.Transfer(context.Background(), 2, func(ctx context.Context, p Part, done int64) (int64, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			if failures[p.N] > 0 {
+				failures[p.N]--
+				return (p.Size - done) / 2, errors.New("test")
+			}
+			return p.Size - done, nil
+		})
+
+		if !errors.Is(err, c.wantErr) || (err == nil) != (c.wantErr == nil) {
+			t.Errorf("Transfer %s returned %v, wanted %v", c.name, err, c.wantErr)
+		}
+		if err == nil && m.Transferred() != c.size {
+			t.Errorf("Transfer %s transferred %d bytes, wanted %d", c.name, m.Transferred(), c.size)
+		}
+	}
+}
+
+func TestMultipartResume(t *testing.T) {
+
+	tryer, err := retry.New(nil, retry.Options{
+		Retries:     1,
+		Base:        time.Millisecond,
+		MaxInterval: time.Millisecond * 5,
+		MaxWait:     time.Second * 1,
+		Exponent:    2,
+	})
+	if err != nil {
+		t.Fatal("Failed to initialise Tryer:\n    ", err.Error())
+	}
+
+	m, err := NewMultipart(tryer, 40, 10, -1)
+	if err != nil {
+		t.Fatal("Failed to initialise Multipart:\n    ", err.Error())
+	}
+
+	// Part 2 gets 4 bytes in and then always fails.
+	broken := true
+	var calls []Part
+	fn := func(ctx context.Context, p Part, done int64) (int64, error) {
+		calls = append(calls, p)
+		if p.N == 2 && broken {
+			return 4 - done, errors.New("test")
+		}
+		return p.Size - done, nil
+	}
+
+	if err := m.Transfer(context.Background(), 1, fn); err == nil {
+		t.Fatal("Transfer succeeded with a broken part")
+	}
+	if m.Done(2) != 4 {
+		t.Errorf("part 2 has %d bytes done after failing, wanted 4", m.Done(2))
+	}
+
+	broken = false
+	calls = nil
+	if err := m.Transfer(context.Background(), 1, fn); err != nil {
+		t.Fatalf("resumed Transfer returned %v, wanted nil", err)
+	}
+	if len(calls) != 2 || calls[0].N != 2 || calls[1].N != 3 {
+		t.Errorf("resumed Transfer called parts %v, wanted only 2 and 3", calls)
+	}
+	if m.Transferred() != 40 {
+		t.Errorf("resumed Transfer transferred %d bytes, wanted 40", m.Transferred())
+	}
+}
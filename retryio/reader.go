@@ -0,0 +1,120 @@
+/*
+Package retryio provides io helpers that recover from failures of the
+underlying source using a retry.Tryer.
+*/
+package retryio
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/jakebowkett/retry"
+)
+
+/*
+	OpenAt opens a source for reading starting at offset bytes from its
+	beginning, for example with an HTTP Range request.
+*/
+type OpenAt = func(ctx context.Context, offset int64) (io.ReadCloser, error)
+
+/*
+	Reader reads from a source that may fail part way through, such as a
+	large download. When opening the source or reading from it fails, the
+	source is reopened at the offset of the last byte successfully read
+	and reading continues, with retries governed by the Tryer.
+
+	Each failure is retried with a fresh call to the Tryer, so Options.Retries
+	limits consecutive failures rather than failures over the whole read.
+*/
+type Reader struct {
+	ctx    context.Context
+	t      *retry.Tryer
+	openAt OpenAt
+	offset int64
+	rc     io.ReadCloser
+	err    error
+}
+
+/*
+	NewReader returns a Reader that reads from the source opened by openAt,
+	starting at offset 0. The context is passed to openAt and to the Tryer.
+*/
+func NewReader(ctx context.Context, t *retry.Tryer, openAt OpenAt) *Reader {
+	return &Reader{ctx: ctx, t: t, openAt: openAt}
+}
+
+/*
+	Offset returns the number of bytes read so far.
+*/
+func (r *Reader) Offset() int64 {
+	return r.offset
+}
+
+func (r *Reader) Read(p []byte) (n int, err error) {
+
+	if r.err != nil {
+		return 0, r.err
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	var eof bool
+	_, err = r.t.TryContext(r.ctx, func(ctx context.Context) error {
+
+		if r.rc == nil {
+			rc, err := r.openAt(ctx, r.offset)
+			if err != nil {
+				return err
+			}
+			r.rc = rc
+		}
+
+		m, err := r.rc.Read(p)
+		n = m
+		r.offset += int64(m)
+
+		switch {
+		case err == io.EOF:
+			eof = true
+			return nil
+		case err != nil:
+			r.rc.Close()
+			r.rc = nil
+			// Bytes already read are returned and the
+			// source is reopened on the next call.
+			if m > 0 {
+				return nil
+			}
+			return err
+		}
+		return nil
+	})
+
+	if err != nil {
+		r.err = err
+		return n, err
+	}
+	if eof {
+		r.err = io.EOF
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+/*
+	Close closes the underlying source if it is open. Further reads return
+	an error.
+*/
+func (r *Reader) Close() error {
+	r.err = errClosed
+	if r.rc == nil {
+		return nil
+	}
+	err := r.rc.Close()
+	r.rc = nil
+	return err
+}
+
+var errClosed = errors.New("retryio: read from closed Reader")
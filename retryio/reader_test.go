@@ -0,0 +1,77 @@
+package retryio
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jakebowkett/retry"
+)
+
+/*
+	flaky reads from src but fails after every limit bytes.
+*/
+type flaky struct {
+	src   []byte
+	off   int
+	limit int
+	read  int
+}
+
+func (f *flaky) Read(p []byte) (int, error) {
+	if f.off >= len(f.src) {
+		return 0, io.EOF
+	}
+	if f.read == f.limit {
+		return 0, errors.New("connection reset")
+	}
+	if len(p) > f.limit-f.read {
+		p = p[:f.limit-f.read]
+	}
+	n := copy(p, f.src[f.off:])
+	f.off += n
+	f.read += n
+	return n, nil
+}
+
+func (f *flaky) Close() error { return nil }
+
+func TestReader(t *testing.T) {
+
+	tryer, err := retry.New(nil, retry.Options{
+		Retries:     2,
+		Base:        time.Millisecond,
+		MaxInterval: time.Millisecond * 5,
+		MaxWait:     time.Second * 1,
+		Exponent:    2,
+	})
+	if err != nil {
+		t.Fatal("Failed to initialise Tryer:\n    ", err.Error())
+	}
+
+	src := []byte(strings.Repeat("0123456789", 10))
+	opens := 0
+
+	r := NewReader(context.Background(), tryer, func(ctx context.Context, offset int64) (io.ReadCloser, error) {
+		opens++
+		if opens%2 == 0 {
+			return nil, errors.New("service unavailable")
+		}
+		return &flaky{src: src, off: int(offset), limit: 7}, nil
+	})
+
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("io.ReadAll(Reader) returned error %v, wanted nil", err)
+	}
+	if !bytes.Equal(got, src) {
+		t.Errorf("io.ReadAll(Reader) = %q\n    wanted %q", got, src)
+	}
+	if r.Offset() != int64(len(src)) {
+		t.Errorf("Reader.Offset() = %d, wanted %d", r.Offset(), len(src))
+	}
+}
@@ -0,0 +1,132 @@
+/*
+Package retrylock waits for distributed locks and leases, such as those
+held in etcd, Consul, Redis or a database table, retrying while another
+holder has the lock and giving up straight away if the caller isn't
+allowed to take it.
+
+	t, err := retry.New(nil, retrylock.Polling(time.Millisecond*200, time.Second*30))
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	waited, err := retrylock.Acquire(ctx, t, nil, func(ctx context.Context) (bool, error) {
+		return lease.TryLock(ctx)
+	})
+*/
+package retrylock
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"strings"
+	"time"
+
+	"github.com/jakebowkett/retry"
+)
+
+/*
+	ErrHeld is the error from the attempts that found the lock held
+	elsewhere. When Acquire gives up it is the LastError of the returned
+	*retry.Error.
+*/
+var ErrHeld = errors.New("retrylock: lock held elsewhere")
+
+/*
+	TryLock makes one attempt to take a lock without waiting. It returns
+	false and a nil error if the lock is held by someone else.
+*/
+type TryLock = func(ctx context.Context) (acquired bool, err error)
+
+/*
+	deniedMessages are parts of the messages lock services use to reject
+	callers that lack permission.
+*/
+var deniedMessages = []string{
+	"permission denied",
+	"access denied",
+	"unauthorized",
+	"unauthenticated",
+	"forbidden",
+	"NOPERM",
+	"NOAUTH",
+}
+
+/*
+	Denied reports whether err means the caller isn't allowed to take the
+	lock, such as fs.ErrPermission or a message like "permission denied",
+	"unauthorized" or Redis's NOPERM. Waiting won't change such an answer.
+*/
+func Denied(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, fs.ErrPermission) {
+		return true
+	}
+	msg := strings.ToLower(err.Error())
+	for _, m := range deniedMessages {
+		if strings.Contains(msg, strings.ToLower(m)) {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+	Acquire calls lock with the backoff of t until it takes the lock, and
+	returns how long it waited. Attempts that find the lock held elsewhere
+	fail with ErrHeld, which the Retry passed to retry.New should allow,
+	as a nil Retry does. Attempts that fail with an error for which denied
+	returns true stop Acquire at once; if denied is nil, Denied is used.
+	Other errors are retried or not according to the Retry.
+
+	The duration is returned on failure too, so callers can report how
+	long they waited before giving up.
+*/
+func Acquire(ctx context.Context, t *retry.Tryer, denied func(err error) bool, lock TryLock) (waited time.Duration, err error) {
+
+	if denied == nil {
+		denied = Denied
+	}
+
+	start := time.Now()
+	_, err = t.TryContext(ctx, func(ctx context.Context) error {
+		ok, err := lock(ctx)
+		switch {
+		case err != nil && denied(err):
+			return retry.Permanent(err)
+		case err != nil:
+			return err
+		case !ok:
+			return ErrHeld
+		}
+		return nil
+	})
+	return time.Since(start), err
+}
+
+/*
+	Polling returns Options for waiting up to maxWait for a lock, trying
+	about every interval. Each delay is random between half of interval
+	and interval, so waiters that lost the same race don't all try again
+	together, and it never grows past interval, so a lock that is freed
+	is noticed promptly however long the wait has been.
+*/
+func Polling(interval, maxWait time.Duration) retry.Options {
+
+	retries := 1
+	if interval > 0 {
+		retries = int(maxWait/(interval/2)) + 1
+	}
+
+	return retry.Options{
+		Retries:     retries,
+		Base:        interval,
+		MaxInterval: interval,
+		MinInterval: interval / 2,
+		MaxWait:     maxWait,
+		Exponent:    1,
+		Jitter:      0.5,
+	}
+}
@@ -0,0 +1,104 @@
+package retrylock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"testing"
+	"time"
+
+	"github.com/jakebowkett/retry"
+)
+
+func TestDenied(t *testing.T) {
+
+	cases := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{errors.New("connection refused"), false},
+		{fmt.Errorf("open lockfile: %w", fs.ErrPermission), true},
+		{errors.New("rpc error: code = PermissionDenied desc = permission denied"), true},
+		{errors.New("NOPERM this user has no permissions to run the 'set' command"), true},
+	}
+
+	for _, c := range cases {
+		if got := Denied(c.err); got != c.want {
+			t.Errorf("Denied(%v) = %t, wanted %t", c.err, got, c.want)
+		}
+	}
+}
+
+func TestAcquire(t *testing.T) {
+
+	errDenied := errors.New("permission denied")
+	errOther := errors.New("connection refused")
+
+	cases := []struct {
+		name      string
+		results   []error // nil means acquired, ErrHeld means held
+		retry     retry.Retry
+		wantErr   error
+		wantCalls int
+	}{
+		{"free", []error{nil}, nil, nil, 1},
+		{"held then freed", []error{ErrHeld, ErrHeld, nil}, nil, nil, 3},
+		{"held throughout", []error{ErrHeld, ErrHeld, ErrHeld, ErrHeld}, nil, retry.ErrMaxRetries, 4},
+		{"denied", []error{ErrHeld, errDenied, nil}, nil, retry.ErrCancelled, 2},
+
+		// Retry decides about errors other than ErrHeld.
+		{"retry refuses", []error{ErrHeld, errOther, nil}, func(err error) bool { return err == ErrHeld }, retry.ErrCancelled, 2},
+	}
+
+	for _, c := range cases {
+
+		tryer, err := retry.New(c.retry, retry.Options{
+			Retries:     3,
+			Base:        time.Millisecond * 2,
+			MaxInterval: time.Millisecond * 2,
+			MaxWait:     time.Second * 1,
+			Exponent:    1,
+		})
+		if err != nil {
+			t.Fatal("Failed to initialise Tryer while testing Acquire:\n    ", err.Error())
+		}
+
+		calls := 0
+		waited, err := Acquire(context.Background(), tryer, nil, func(ctx context.Context) (bool, error) {
+			calls++
+			switch r := c.results[calls-1]; r {
+			case nil:
+				return true, nil
+			case ErrHeld:
+				return false, nil
+			default:
+				return false, r
+			}
+		})
+
+		if !errors.Is(err, c.wantErr) || (err == nil) != (c.wantErr == nil) || calls != c.wantCalls {
+			t.Errorf("Acquire %s returned %v after %d calls, wanted %v after %d",
+				c.name, err, calls, c.wantErr, c.wantCalls)
+		}
+		if min := time.Duration(calls-1) * time.Millisecond * 2; waited < min {
+			t.Errorf("Acquire %s reported waiting %s, wanted at least %s", c.name, waited, min)
+		}
+	}
+}
+
+func TestPolling(t *testing.T) {
+
+	o := Polling(time.Millisecond*200, time.Second*30)
+	tryer, err := retry.New(nil, o)
+	if err != nil {
+		t.Fatal("Polling returned invalid Options:\n    ", err.Error())
+	}
+
+	for n, d := range tryer.Schedule(o.Retries) {
+		if d != time.Millisecond*200 {
+			t.Fatalf("Polling delay before retry %d is %s, wanted 200ms", n+1, d)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+/*
+Package retrynet provides helpers for retrying network operations with
+a retry.Tryer.
+
+	t, err := retry.New(nil, retry.Options{
+		Retries:     5,
+		Base:        time.Millisecond * 100,
+		MaxInterval: time.Second * 2,
+		MaxWait:     time.Second * 10,
+		Exponent:    2,
+		Jitter:      0.5,
+	})
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	conn, err := retrynet.DialContext(ctx, t, nil, "tcp", "db.internal:5432")
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer conn.Close()
+*/
+package retrynet
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	"github.com/jakebowkett/retry"
+	"github.com/jakebowkett/retry/retrysys"
+)
+
+/*
+	DialContext connects to address on the named network using d, retrying
+	failed connection attempts with the backoff of t. If d is nil a zero
+	net.Dialer is used.
+
+	Only errors for which Transient returns true are retried, in addition
+	to any classification done by the Retry passed to retry.New. On failure
+	the error is the one returned by t.TryContext.
+*/
+func DialContext(ctx context.Context, t *retry.Tryer, d *net.Dialer, network, address string) (net.Conn, error) {
+
+	if d == nil {
+		d = &net.Dialer{}
+	}
+
+	var conn net.Conn
+	_, err := t.TryContext(ctx, func(ctx context.Context) error {
+		c, err := d.DialContext(ctx, network, address)
+		if err != nil {
+			if !Transient(err) {
+				return retry.Permanent(err)
+			}
+			return err
+		}
+		conn = c
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return conn, nil
+}
+
+/*
+	Transient reports whether err, returned from dialing or otherwise
+	using a network connection, is likely to go away if the operation is
+	tried again. Timeouts, refused or reset connections, unreachable hosts
+	and temporary DNS failures are transient. Unknown hosts, malformed
+	addresses, cancellation and errors for which PermanentTLS returns true
+	are not.
+*/
+func Transient(err error) bool {
+
+	if err == nil {
+		return false
+	}
+
+	if errors.Is(err, context.Canceled) || PermanentTLS(err) {
+		return false
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return dnsErr.IsTimeout || dnsErr.IsTemporary
+	}
+
+	var addrErr *net.AddrError
+	if errors.As(err, &addrErr) {
+		return false
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	return retrysys.Transient(err)
+}
@@ -0,0 +1,55 @@
+package retrynet
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/jakebowkett/retry"
+)
+
+func newTryer(t *testing.T) *retry.Tryer {
+	tryer, err := retry.New(nil, retry.Options{
+		Retries:     2,
+		Base:        time.Millisecond * 5,
+		MaxInterval: time.Millisecond * 20,
+		MaxWait:     time.Second * 1,
+		Exponent:    2,
+	})
+	if err != nil {
+		t.Fatal("Failed to initialise Tryer:\n    ", err.Error())
+	}
+	return tryer
+}
+
+func TestDialContext(t *testing.T) {
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	open := ln.Addr().String()
+
+	conn, err := DialContext(context.Background(), newTryer(t), nil, "tcp", open)
+	if err != nil {
+		t.Fatalf("DialContext(%q) returned error %v, wanted nil", open, err)
+	}
+	conn.Close()
+
+	// Closing the listener frees the port so dialing it
+	// is refused, which is transient and exhausts retries.
+	ln.Close()
+
+	_, err = DialContext(context.Background(), newTryer(t), nil, "tcp", open)
+	if !errors.Is(err, retry.ErrMaxRetries) {
+		t.Errorf("DialContext(%q) returned error %v, wanted %v", open, err, retry.ErrMaxRetries)
+	}
+
+	// A malformed address is permanent.
+	_, err = DialContext(context.Background(), newTryer(t), nil, "tcp", "127.0.0.1")
+	if !errors.Is(err, retry.ErrCancelled) {
+		t.Errorf("DialContext(%q) returned error %v, wanted %v", "127.0.0.1", err, retry.ErrCancelled)
+	}
+}
@@ -0,0 +1,132 @@
+package retrynet
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jakebowkett/retry"
+)
+
+/*
+	State is the state of a connection managed by Supervise.
+*/
+type State int
+
+const (
+	Connecting   State = iota // Establishing a connection.
+	Connected                 // Connected and running.
+	Disconnected              // The connection failed or ended; waiting to reconnect.
+	Stopped                   // Supervise has returned.
+)
+
+func (s State) String() string {
+	switch s {
+	case Connecting:
+		return "connecting"
+	case Connected:
+		return "connected"
+	case Disconnected:
+		return "disconnected"
+	case Stopped:
+		return "stopped"
+	}
+	return fmt.Sprintf("State(%d)", int(s))
+}
+
+/*
+	SuperviseOptions configures Supervise.
+*/
+type SuperviseOptions struct {
+	/*
+		HealthyAfter is how long a connection must stay up before it is
+		considered healthy. When a healthy connection ends the backoff is
+		reset, so the next reconnect waits only the Tryer's base delay.
+		Connections that end sooner keep growing the backoff as though
+		connecting had failed. If HealthyAfter is 0 the backoff is reset
+		whenever a connection is established.
+	*/
+	HealthyAfter time.Duration
+
+	/*
+		OnStateChange is an optional hook called whenever the state of the
+		supervised connection changes. The err parameter is the error that
+		caused the change, if any.
+	*/
+	OnStateChange func(from, to State, err error)
+}
+
+/*
+	Supervise keeps a long-lived connection, such as a WebSocket or TCP
+	stream, running. It calls connect to establish a connection and then
+	run to use it until run returns. Whenever connect fails or run returns
+	an error, Supervise waits according to t's backoff and reconnects.
+
+	Unlike Try, Supervise ignores t's Retries and MaxWait and continues
+	until ctx is done, run returns nil, or connect or run return an error
+	marked with retry.Permanent. It returns nil if run returned nil and an
+	error otherwise.
+*/
+func Supervise[C any](
+	ctx context.Context,
+	t *retry.Tryer,
+	o SuperviseOptions,
+	connect func(ctx context.Context) (C, error),
+	run func(ctx context.Context, conn C) error,
+) error {
+
+	state := Stopped
+	set := func(to State, err error) {
+		if o.OnStateChange != nil && to != state {
+			o.OnStateChange(state, to, err)
+		}
+		state = to
+	}
+
+	failures := 0
+	var timer *time.Timer
+	for {
+
+		set(Connecting, nil)
+
+		conn, err := connect(ctx)
+		if err == nil {
+			set(Connected, nil)
+			start := time.Now()
+			err = run(ctx, conn)
+			if err == nil {
+				set(Stopped, nil)
+				return nil
+			}
+			if time.Since(start) >= o.HealthyAfter {
+				failures = 0
+			}
+		}
+
+		set(Disconnected, err)
+
+		if ctx.Err() != nil {
+			set(Stopped, ctx.Err())
+			return context.Cause(ctx)
+		}
+		if retry.IsPermanent(err) {
+			set(Stopped, err)
+			return err
+		}
+
+		if timer == nil {
+			timer = time.NewTimer(t.Delay(failures))
+		} else {
+			timer.Reset(t.Delay(failures))
+		}
+		failures++
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			set(Stopped, ctx.Err())
+			return context.Cause(ctx)
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package retrynet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jakebowkett/retry"
+)
+
+func TestSupervise(t *testing.T) {
+
+	var states []State
+	connects := 0
+	runs := 0
+
+	err := Supervise(context.Background(), newTryer(t),
+		SuperviseOptions{
+			OnStateChange: func(from, to State, err error) {
+				states = append(states, to)
+			},
+		},
+		func(context.Context) (int, error) {
+			connects++
+			if connects == 1 {
+				return 0, errors.New("refused")
+			}
+			return connects, nil
+		},
+		func(ctx context.Context, conn int) error {
+			runs++
+			if runs == 1 {
+				return errors.New("connection reset")
+			}
+			return retry.Permanent(errors.New("closed by peer"))
+		},
+	)
+
+	if !retry.IsPermanent(err) {
+		t.Errorf("Supervise returned %v, wanted a permanent error", err)
+	}
+
+	want := "[connecting disconnected connecting connected disconnected " +
+		"connecting connected disconnected stopped]"
+	if got := fmt.Sprint(states); got != want {
+		t.Errorf("Supervise states = %s\n    wanted %s", got, want)
+	}
+}
@@ -0,0 +1,99 @@
+package retrynet
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+
+	"github.com/jakebowkett/retry"
+)
+
+/*
+	permanentAlerts are the TLS alerts a peer sends when it will never
+	accept the handshake as configured, as opposed to failing to complete
+	it this time.
+*/
+var permanentAlerts = map[tls.AlertError]bool{
+	40:  true, // handshake_failure
+	42:  true, // bad_certificate
+	43:  true, // unsupported_certificate
+	44:  true, // certificate_revoked
+	45:  true, // certificate_expired
+	46:  true, // certificate_unknown
+	48:  true, // unknown_ca
+	70:  true, // protocol_version
+	71:  true, // insufficient_security
+	112: true, // unrecognized_name
+	116: true, // certificate_required
+	120: true, // no_application_protocol
+}
+
+/*
+	PermanentTLS reports whether err is a TLS handshake failure that will
+	happen again however often the handshake is retried: a certificate
+	that is invalid, expired, for another host, or signed by an unknown
+	authority, an alert from the peer rejecting the certificate or the
+	protocol version, or a peer that doesn't speak TLS at all. Retrying
+	such errors only hammers an endpoint that is misconfigured.
+
+	Handshakes that time out or whose connection is reset are not
+	permanent, and Transient reports them as transient.
+*/
+func PermanentTLS(err error) bool {
+
+	if err == nil {
+		return false
+	}
+
+	var (
+		verifyErr    *tls.CertificateVerificationError
+		authorityErr x509.UnknownAuthorityError
+		invalidErr   x509.CertificateInvalidError
+		hostnameErr  x509.HostnameError
+		headerErr    tls.RecordHeaderError
+		alert        tls.AlertError
+	)
+	switch {
+	case errors.As(err, &verifyErr),
+		errors.As(err, &authorityErr),
+		errors.As(err, &invalidErr),
+		errors.As(err, &hostnameErr),
+		errors.As(err, &headerErr):
+		return true
+	case errors.As(err, &alert):
+		return permanentAlerts[alert]
+	}
+	return false
+}
+
+/*
+	DialTLSContext connects to address on the named network and performs
+	a TLS handshake using d, retrying failures with the backoff of t like
+	DialContext. Handshakes failing with errors for which PermanentTLS
+	returns true are not retried. If d is nil a zero tls.Dialer is used.
+*/
+func DialTLSContext(ctx context.Context, t *retry.Tryer, d *tls.Dialer, network, address string) (net.Conn, error) {
+
+	if d == nil {
+		d = &tls.Dialer{}
+	}
+
+	var conn net.Conn
+	_, err := t.TryContext(ctx, func(ctx context.Context) error {
+		c, err := d.DialContext(ctx, network, address)
+		if err != nil {
+			if !Transient(err) {
+				return retry.Permanent(err)
+			}
+			return err
+		}
+		conn = c
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return conn, nil
+}
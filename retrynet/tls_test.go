@@ -0,0 +1,70 @@
+package retrynet
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jakebowkett/retry"
+)
+
+func TestPermanentTLS(t *testing.T) {
+
+	cases := []struct {
+		err           error
+		wantPermanent bool
+		wantTransient bool
+	}{
+		{errors.New("test"), false, false},
+		{x509.UnknownAuthorityError{}, true, false},
+		{&tls.CertificateVerificationError{Err: x509.HostnameError{}}, true, false},
+		{fmt.Errorf("remote error: %w", tls.AlertError(45)), true, false},
+		{tls.RecordHeaderError{Msg: "first record does not look like a TLS handshake"}, true, false},
+
+		// A close_notify alert isn't about the configuration.
+		{tls.AlertError(0), false, false},
+
+		// Handshakes that time out are transient.
+		{fmt.Errorf("tls handshake: %w", context.DeadlineExceeded), false, true},
+	}
+
+	for _, c := range cases {
+		if got := PermanentTLS(c.err); got != c.wantPermanent {
+			t.Errorf("PermanentTLS(%v) = %t, wanted %t", c.err, got, c.wantPermanent)
+		}
+		if got := Transient(c.err); got != c.wantTransient {
+			t.Errorf("Transient(%v) = %t, wanted %t", c.err, got, c.wantTransient)
+		}
+	}
+}
+
+func TestDialTLSContext(t *testing.T) {
+
+	srv := httptest.NewTLSServer(http.NotFoundHandler())
+	defer srv.Close()
+	addr := srv.Listener.Addr().String()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(srv.Certificate())
+	d := &tls.Dialer{Config: &tls.Config{RootCAs: pool}}
+
+	conn, err := DialTLSContext(context.Background(), newTryer(t), d, "tcp", addr)
+	if err != nil {
+		t.Fatalf("DialTLSContext(%q) returned error %v, wanted nil", addr, err)
+	}
+	conn.Close()
+
+	// The test certificate isn't trusted by default, which
+	// is permanent so only one attempt is made.
+	_, err = DialTLSContext(context.Background(), newTryer(t), nil, "tcp", addr)
+	var re *retry.Error
+	if !errors.As(err, &re) || !errors.Is(err, retry.ErrCancelled) || re.Attempts() != 1 || !PermanentTLS(re.LastError()) {
+		t.Errorf("DialTLSContext(%q) with an untrusted certificate returned error %v, wanted %v after 1 attempt",
+			addr, err, retry.ErrCancelled)
+	}
+}
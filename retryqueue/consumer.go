@@ -0,0 +1,117 @@
+/*
+Package retryqueue helps message queue consumers retry the processing of
+each message with a retry.Tryer and dead-letter the ones that keep failing.
+
+	c := &retryqueue.Consumer[*Job]{
+		Tryer:          t,
+		MessageTimeout: time.Minute,
+		Process: func(ctx context.Context, job *Job) error {
+			return job.Run(ctx)
+		},
+		DeadLetter: func(ctx context.Context, job *Job, errs []error, err error) error {
+			return dlq.Publish(ctx, job, len(errs))
+		},
+	}
+
+	for msg := range deliveries {
+		if err := c.Handle(ctx, msg); err != nil {
+			msg.Nack()
+			continue
+		}
+		msg.Ack()
+	}
+*/
+package retryqueue
+
+import (
+	"context"
+	"time"
+
+	"github.com/jakebowkett/retry"
+)
+
+/*
+	Consumer processes messages of type M, retrying failures and handing
+	messages that cannot be processed to a dead letter callback.
+*/
+type Consumer[M any] struct {
+	/*
+		Tryer retries Process for each message.
+	*/
+	Tryer *retry.Tryer
+
+	/*
+		Process handles a single message. It is retried according to Tryer
+		until it succeeds or Tryer gives up.
+	*/
+	Process func(ctx context.Context, msg M) error
+
+	/*
+		DeadLetter is called once for a message when Tryer gives up on
+		processing it, with the errors from each attempt and the error from
+		Tryer. It might republish the message to a dead letter queue or
+		park it for inspection. If DeadLetter returns nil the message is
+		considered handled. If DeadLetter is nil the message is considered
+		unhandled and Handle returns the error from Tryer.
+	*/
+	DeadLetter func(ctx context.Context, msg M, errs []error, err error) error
+
+	/*
+		MessageTimeout is the most time spent processing one message,
+		including waiting between attempts. It ensures one poison message
+		can't stall the consumer. If it is 0 there is no limit other than
+		Tryer's MaxWait.
+	*/
+	MessageTimeout time.Duration
+}
+
+/*
+	Handle processes msg. It returns nil if msg was processed or dead
+	lettered, and an error if msg should be redelivered, which is the case
+	when ctx is done or when dead lettering fails.
+*/
+func (c *Consumer[M]) Handle(ctx context.Context, msg M) error {
+
+	mctx := ctx
+	if c.MessageTimeout > 0 {
+		var cancel context.CancelFunc
+		mctx, cancel = context.WithTimeout(ctx, c.MessageTimeout)
+		defer cancel()
+	}
+
+	errs, err := c.Tryer.TryContext(mctx, func(ctx context.Context) error {
+		return c.Process(ctx, msg)
+	})
+	if err == nil {
+		return nil
+	}
+
+	// Shutting down isn't the message's fault.
+	if ctx.Err() != nil || c.DeadLetter == nil {
+		return err
+	}
+
+	return c.DeadLetter(ctx, msg, errs, err)
+}
+
+/*
+	Consume calls Handle for each message received from msgs until msgs is
+	closed or ctx is done. Messages are handled one at a time; run several
+	Consume calls to process messages concurrently. Consume returns the
+	first error from Handle, leaving the remaining messages unread.
+*/
+func (c *Consumer[M]) Consume(ctx context.Context, msgs <-chan M) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return context.Cause(ctx)
+		case msg, ok := <-msgs:
+			if !ok {
+				return nil
+			}
+			if err := c.Handle(ctx, msg); err != nil {
+				return err
+			}
+		}
+	}
+}
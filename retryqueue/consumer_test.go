@@ -0,0 +1,69 @@
+package retryqueue
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jakebowkett/retry"
+)
+
+func TestConsumer(t *testing.T) {
+
+	tryer, err := retry.New(nil, retry.Options{
+		Retries:     2,
+		Base:        time.Millisecond,
+		MaxInterval: time.Millisecond * 5,
+		MaxWait:     time.Second * 1,
+		Exponent:    2,
+	})
+	if err != nil {
+		t.Fatal("Failed to initialise Tryer:\n    ", err.Error())
+	}
+
+	attempts := map[string]int{}
+	dead := map[string]int{}
+
+	c := &Consumer[string]{
+		Tryer:          tryer,
+		MessageTimeout: time.Millisecond * 100,
+		Process: func(ctx context.Context, msg string) error {
+			attempts[msg]++
+			switch msg {
+			case "poison":
+				return errors.New("can't parse")
+			case "slow":
+				<-ctx.Done()
+				return ctx.Err()
+			}
+			return nil
+		},
+		DeadLetter: func(ctx context.Context, msg string, errs []error, err error) error {
+			dead[msg] = len(errs)
+			return nil
+		},
+	}
+
+	msgs := make(chan string, 3)
+	msgs <- "ok"
+	msgs <- "poison"
+	msgs <- "slow"
+	close(msgs)
+
+	if err := c.Consume(context.Background(), msgs); err != nil {
+		t.Fatalf("Consumer.Consume returned %v, wanted nil", err)
+	}
+
+	if attempts["ok"] != 1 || dead["ok"] != 0 {
+		t.Errorf("message ok: %d attempts, dead lettered after %d, wanted 1 and 0",
+			attempts["ok"], dead["ok"])
+	}
+	if attempts["poison"] != 3 || dead["poison"] != 3 {
+		t.Errorf("message poison: %d attempts, dead lettered after %d, wanted 3 and 3",
+			attempts["poison"], dead["poison"])
+	}
+	if _, ok := dead["slow"]; !ok {
+		t.Error("message slow was not dead lettered after MessageTimeout")
+	}
+}
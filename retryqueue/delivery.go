@@ -0,0 +1,106 @@
+package retryqueue
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jakebowkett/retry"
+)
+
+/*
+	Delivery returns Options for outbound delivery jobs such as webhooks
+	and emails, where the receiver may be down for hours and delivering
+	late is much better than not delivering. Delays start at half a
+	minute and grow to an hour, and the Tryer gives up after a day.
+*/
+func Delivery() retry.Options {
+	return retry.Options{
+		Retries:     30,
+		Base:        time.Second * 30,
+		MaxInterval: time.Hour,
+		MaxWait:     time.Hour * 24,
+		Exponent:    2,
+		Jitter:      0.5,
+	}
+}
+
+/*
+	Destinations retries deliveries with a separate Tryer and Throttle for
+	each destination, such as a webhook URL or a mail domain. While one
+	destination keeps failing its Throttle stops further retries to it,
+	so deliveries to it are handed back after a single attempt instead of
+	occupying a sender for the whole of a long retry horizon, and
+	deliveries to other destinations are unaffected.
+
+	A Destinations is safe for concurrent use.
+*/
+type Destinations struct {
+	retry     retry.Retry
+	opts      retry.Options
+	maxTokens float64
+	ratio     float64
+	mu        sync.Mutex
+	tryers    map[string]*retry.Tryer
+}
+
+/*
+	NewDestinations returns a Destinations that creates the Tryer for each
+	destination from r and o, with a Throttle made by retry.NewThrottle
+	with maxTokens and tokenRatio in place of o.Throttle. It returns an
+	error if any of these are invalid.
+*/
+func NewDestinations(r retry.Retry, o retry.Options, maxTokens, tokenRatio float64) (*Destinations, error) {
+
+	d := &Destinations{
+		retry:     r,
+		opts:      o,
+		maxTokens: maxTokens,
+		ratio:     tokenRatio,
+		tryers:    make(map[string]*retry.Tryer),
+	}
+
+	// Validate now so Deliver can't fail for configuration reasons.
+	if _, err := d.newTryer(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (d *Destinations) newTryer() (*retry.Tryer, error) {
+	th, err := retry.NewThrottle(d.maxTokens, d.ratio)
+	if err != nil {
+		return nil, err
+	}
+	o := d.opts
+	o.Throttle = th
+	return retry.New(d.retry, o)
+}
+
+/*
+	Tryer returns the Tryer used for dest, creating it if necessary.
+*/
+func (d *Destinations) Tryer(dest string) *retry.Tryer {
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	t, ok := d.tryers[dest]
+	if !ok {
+		// The options were validated by NewDestinations.
+		t, _ = d.newTryer()
+		d.tryers[dest] = t
+	}
+	return t
+}
+
+/*
+	Deliver calls fn with the Tryer for dest and returns the error from
+	TryContext. When the error is retry.ErrThrottled the destination is
+	failing and the delivery should be scheduled again later, for example
+	by leaving the message on its queue.
+*/
+func (d *Destinations) Deliver(ctx context.Context, dest string, fn retry.OperationCtx) error {
+	_, err := d.Tryer(dest).TryContext(ctx, fn)
+	return err
+}
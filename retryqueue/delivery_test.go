@@ -0,0 +1,63 @@
+package retryqueue
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jakebowkett/retry"
+)
+
+func TestDelivery(t *testing.T) {
+	if _, err := retry.New(nil, Delivery()); err != nil {
+		t.Errorf("Delivery returned invalid Options: %s", err)
+	}
+}
+
+func TestDestinations(t *testing.T) {
+
+	d, err := NewDestinations(nil, retry.Options{
+		Retries:     5,
+		Base:        time.Millisecond,
+		MaxInterval: time.Millisecond * 5,
+		MaxWait:     time.Second * 1,
+		Exponent:    2,
+	}, 4, 0.5)
+	if err != nil {
+		t.Fatal("Failed to initialise Destinations:\n    ", err.Error())
+	}
+
+	attempts := map[string]int{}
+	deliver := func(dest string) error {
+		return d.Deliver(context.Background(), dest, func(ctx context.Context) error {
+			attempts[dest]++
+			if dest == "dead" {
+				return errors.New("connection refused")
+			}
+			return nil
+		})
+	}
+
+	// The dead destination's Throttle drains after 2 failures,
+	// after which each delivery gets a single attempt.
+	for i := 0; i < 3; i++ {
+		if err := deliver("dead"); !errors.Is(err, retry.ErrThrottled) {
+			t.Errorf("delivery %d to dead destination returned %v, wanted %v", i, err, retry.ErrThrottled)
+		}
+	}
+	if attempts["dead"] != 4 {
+		t.Errorf("made %d attempts to dead destination, wanted 4", attempts["dead"])
+	}
+
+	if err := deliver("live"); err != nil {
+		t.Errorf("delivery to live destination returned %v, wanted nil", err)
+	}
+	if d.Tryer("dead") == d.Tryer("live") {
+		t.Error("destinations share a Tryer")
+	}
+
+	if _, err := NewDestinations(nil, Delivery(), 0, 0.5); err == nil {
+		t.Error("NewDestinations accepted maxTokens of 0")
+	}
+}
@@ -0,0 +1,200 @@
+/*
+Package retrysql retries database transactions that fail because they
+conflicted with concurrent transactions. The whole transaction is the
+correct unit of retry: retrying one statement of a transaction that the
+database has rolled back, or is about to, only produces more errors.
+
+	err := retrysql.WithTx(ctx, t, db, nil, func(ctx context.Context, tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, "UPDATE accounts SET balance = balance - $1 WHERE id = $2", amount, from)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, "UPDATE accounts SET balance = balance + $1 WHERE id = $2", amount, to)
+		return err
+	})
+
+The package doesn't depend on any driver. It recognises errors by the
+SQLSTATE reported by drivers such as pgx and lib/pq, and otherwise by the
+messages PostgreSQL, MySQL, and SQLite use for these conflicts.
+*/
+package retrysql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strings"
+
+	"github.com/jakebowkett/retry"
+)
+
+/*
+	sqlStater is implemented by errors from PostgreSQL drivers such as
+	pgx and lib/pq.
+*/
+type sqlStater interface{ SQLState() string }
+
+/*
+	conflictStates are the SQLSTATE codes for serialization failures
+	and deadlocks.
+*/
+var conflictStates = map[string]bool{
+	"40001": true, // serialization_failure
+	"40P01": true, // deadlock_detected
+}
+
+/*
+	conflictMessages are parts of the messages databases use for
+	serialization failures, deadlocks, and lock timeouts, for drivers
+	that don't report a SQLSTATE.
+*/
+var conflictMessages = []string{
+	"could not serialize access", // PostgreSQL
+	"deadlock detected",          // PostgreSQL
+	"Deadlock found",             // MySQL 1213
+	"Lock wait timeout exceeded", // MySQL 1205
+	"database is locked",         // SQLite SQLITE_BUSY
+	"database table is locked",   // SQLite SQLITE_LOCKED
+	"restart transaction",        // CockroachDB
+	"Transaction was deadlocked", // SQL Server 1205
+	"serialization failure",      // others
+}
+
+/*
+	Conflict reports whether err means a transaction failed because it
+	conflicted with another one, such as a serialization failure or a
+	deadlock, so it may succeed if it is run again from the start.
+*/
+func Conflict(err error) bool {
+	if err == nil {
+		return false
+	}
+	var s sqlStater
+	if errors.As(err, &s) && conflictStates[s.SQLState()] {
+		return true
+	}
+	msg := err.Error()
+	for _, m := range conflictMessages {
+		if strings.Contains(msg, m) {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+	badConnMessages are parts of the messages drivers use when a pooled
+	connection turns out to be dead, for drivers that don't return
+	driver.ErrBadConn.
+*/
+var badConnMessages = []string{
+	"bad connection",
+	"connection is already closed",
+	"conn closed",
+	"server closed the connection unexpectedly",
+}
+
+/*
+	BadConn reports whether err means the connection used was dead, such
+	as driver.ErrBadConn, sql.ErrConnDone, or a driver's message for a
+	closed connection. Trying again usually succeeds straight away, as
+	database/sql discards the dead connection and picks another.
+*/
+func BadConn(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) {
+		return true
+	}
+	msg := err.Error()
+	for _, m := range badConnMessages {
+		if strings.Contains(msg, m) {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+	Retryable reports whether err is a Conflict or a BadConn. It is
+	suitable for passing to retry.New for Tryers that retry individual
+	statements outside a transaction.
+*/
+func Retryable(err error) bool {
+	return Conflict(err) || BadConn(err)
+}
+
+/*
+	RetryBadConn returns a Middleware that repeats an attempt straight
+	away when it fails with a BadConn, without waiting for the backoff and
+	without counting against Options.Retries. It does so at most once per
+	call, so if the pool hands out several dead connections in a row the
+	later failures are retried with the usual backoff. Add it to
+	Options.Middleware to get this two tier behaviour; without it, dead
+	connections are retried like any other error.
+
+	A statement that failed on a dead connection may still have run, so
+	only use RetryBadConn for operations that are safe to repeat.
+*/
+func RetryBadConn() retry.Middleware {
+	return func(next retry.OperationCtx) retry.OperationCtx {
+		repeated := false
+		return func(ctx context.Context) error {
+			err := next(ctx)
+			if err == nil || repeated || !BadConn(err) {
+				return err
+			}
+			repeated = true
+			return next(ctx)
+		}
+	}
+}
+
+/*
+	WithTx runs fn in a transaction on db begun with opts and commits it,
+	retrying the whole transaction with t when it fails because of a
+	Conflict, or because of a BadConn before it was committed. When fn
+	returns an error the transaction is rolled back. Other errors stop the
+	retries immediately, as does a BadConn from Commit, since the
+	transaction may have been committed before the connection died. fn
+	may be called several times so it must not have effects outside the
+	transaction, or those effects must be safe to repeat.
+*/
+func WithTx(
+	ctx context.Context,
+	t *retry.Tryer,
+	db *sql.DB,
+	opts *sql.TxOptions,
+	fn func(ctx context.Context, tx *sql.Tx) error,
+) error {
+	_, err := t.TryContext(ctx, func(ctx context.Context) error {
+		committing, err := tx(ctx, db, opts, fn)
+		if err == nil || Conflict(err) || BadConn(err) && !committing {
+			return err
+		}
+		return retry.Permanent(err)
+	})
+	return err
+}
+
+/*
+	tx runs a single attempt of WithTx. committing reports whether the
+	error came from Commit.
+*/
+func tx(ctx context.Context, db *sql.DB, opts *sql.TxOptions,
+	fn func(context.Context, *sql.Tx) error) (committing bool, err error) {
+
+	tx, err := db.BeginTx(ctx, opts)
+	if err != nil {
+		return false, err
+	}
+
+	if err := fn(ctx, tx); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
@@ -0,0 +1,214 @@
+package retrysql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jakebowkett/retry"
+)
+
+/*
+	fakeDriver is a database/sql driver whose connections only support
+	transactions, counting how many are begun, committed, and rolled back.
+*/
+type fakeDriver struct {
+	mu        sync.Mutex
+	begun     int
+	committed int
+	rolled    int
+	commitErr []error // returned by successive commits
+}
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) { return &fakeConn{d}, nil }
+
+type fakeConn struct{ d *fakeDriver }
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c *fakeConn) Close() error                        { return nil }
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	c.d.mu.Lock()
+	defer c.d.mu.Unlock()
+	c.d.begun++
+	return &fakeTx{c.d}, nil
+}
+
+type fakeTx struct{ d *fakeDriver }
+
+func (tx *fakeTx) Commit() error {
+	tx.d.mu.Lock()
+	defer tx.d.mu.Unlock()
+	tx.d.committed++
+	if len(tx.d.commitErr) > 0 {
+		err := tx.d.commitErr[0]
+		tx.d.commitErr = tx.d.commitErr[1:]
+		return err
+	}
+	return nil
+}
+
+func (tx *fakeTx) Rollback() error {
+	tx.d.mu.Lock()
+	defer tx.d.mu.Unlock()
+	tx.d.rolled++
+	return nil
+}
+
+type stateError string
+
+func (e stateError) Error() string    { return "sql error " + string(e) }
+func (e stateError) SQLState() string { return string(e) }
+
+func TestConflict(t *testing.T) {
+
+	cases := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{errors.New("syntax error"), false},
+		{stateError("40001"), true},
+		{fmt.Errorf("update: %w", stateError("40P01")), true},
+		{stateError("23505"), false},
+		{errors.New("Error 1213 (40001): Deadlock found when trying to get lock"), true},
+		{errors.New("database is locked"), true},
+	}
+
+	for _, c := range cases {
+		if got := Conflict(c.err); got != c.want {
+			t.Errorf("Conflict(%v) = %t, wanted %t", c.err, got, c.want)
+		}
+	}
+}
+
+func TestWithTx(t *testing.T) {
+
+	tryer, err := retry.New(nil, retry.Options{
+		Retries:     3,
+		Base:        time.Millisecond * 1,
+		MaxInterval: time.Millisecond * 5,
+		MaxWait:     time.Second * 1,
+		Exponent:    2,
+	})
+	if err != nil {
+		t.Fatal("Failed to initialise Tryer:\n    ", err.Error())
+	}
+
+	errSyntax := errors.New("syntax error")
+
+	cases := []struct {
+		name          string
+		fnErrs        []error // returned by successive calls of fn
+		commitErrs    []error // returned by successive commits
+		wantErr       error
+		wantBegun     int
+		wantCommitted int
+		wantRolled    int
+	}{
+		{"success", []error{nil}, nil, nil, 1, 1, 0},
+		{"conflict in fn", []error{stateError("40001"), nil}, nil, nil, 2, 1, 1},
+		{"conflict on commit", []error{nil, nil}, []error{stateError("40001")}, nil, 2, 2, 0},
+		{"other error", []error{errSyntax}, nil, retry.ErrCancelled, 1, 0, 1},
+		{"dead connection in fn", []error{driver.ErrBadConn, nil}, nil, nil, 2, 1, 1},
+		{"dead connection on commit", []error{nil}, []error{driver.ErrBadConn}, retry.ErrCancelled, 1, 1, 0},
+	}
+
+	for _, c := range cases {
+
+		d := &fakeDriver{commitErr: c.commitErrs}
+		db := sql.OpenDB(connector{d})
+
+		calls := 0
+		err := WithTx(context.Background(), tryer, db, nil, func(ctx context.Context, tx *sql.Tx) error {
+			calls++
+			return c.fnErrs[calls-1]
+		})
+		db.Close()
+
+		if !errors.Is(err, c.wantErr) || c.wantErr == nil && err != nil ||
+			d.begun != c.wantBegun || d.committed != c.wantCommitted || d.rolled != c.wantRolled {
+			t.Errorf("WithTx %s returned %v with %d begun, %d committed, %d rolled back, "+
+				"wanted %v with %d, %d, %d",
+				c.name, err, d.begun, d.committed, d.rolled,
+				c.wantErr, c.wantBegun, c.wantCommitted, c.wantRolled)
+		}
+	}
+}
+
+type connector struct{ d *fakeDriver }
+
+func (c connector) Connect(context.Context) (driver.Conn, error) { return c.d.Open("") }
+func (c connector) Driver() driver.Driver                        { return c.d }
+
+func TestBadConn(t *testing.T) {
+
+	cases := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{errors.New("syntax error"), false},
+		{driver.ErrBadConn, true},
+		{fmt.Errorf("query: %w", sql.ErrConnDone), true},
+		{errors.New("pq: server closed the connection unexpectedly"), true},
+	}
+
+	for _, c := range cases {
+		if got := BadConn(c.err); got != c.want {
+			t.Errorf("BadConn(%v) = %t, wanted %t", c.err, got, c.want)
+		}
+	}
+}
+
+func TestRetryBadConn(t *testing.T) {
+
+	cases := []struct {
+		name      string
+		results   []error // returned by successive attempts
+		wantErr   error
+		wantCalls int
+		wantWaits int
+	}{
+		// The repeat fixes the operation without waiting.
+		{"repeat succeeds", []error{driver.ErrBadConn, nil}, nil, 2, 0},
+
+		// Only one immediate repeat is made per call.
+		{"still bad", []error{driver.ErrBadConn, driver.ErrBadConn, nil}, nil, 3, 1},
+
+		// Other errors use the usual backoff.
+		{"other error", []error{errors.New("test"), nil}, nil, 2, 1},
+	}
+
+	for _, c := range cases {
+
+		waits := 0
+		tryer, err := retry.New(nil, retry.Options{
+			Retries:     3,
+			Base:        time.Millisecond * 1,
+			MaxInterval: time.Millisecond * 5,
+			MaxWait:     time.Second * 1,
+			Exponent:    2,
+			Middleware:  []retry.Middleware{RetryBadConn()},
+			OnRetry:     func(retry.State, error) { waits++ },
+		})
+		if err != nil {
+			t.Fatal("Failed to initialise Tryer:\n    ", err.Error())
+		}
+
+		calls := 0
+		_, err = tryer.Try(func() error {
+			calls++
+			return c.results[calls-1]
+		})
+		if err != c.wantErr || calls != c.wantCalls || waits != c.wantWaits {
+			t.Errorf("Try %s returned %v after %d calls and %d waits, wanted %v after %d and %d",
+				c.name, err, calls, waits, c.wantErr, c.wantCalls, c.wantWaits)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+/*
+Package retrysys classifies low-level system call errors the same way on
+every operating system. The errno constants in package syscall only match
+the errors Unix reports, so a predicate written with errors.Is and
+syscall.ECONNRESET silently stops retrying on Windows, where a reset
+connection is WSAECONNRESET. Transient covers both.
+
+	if retrysys.Transient(err) {
+		return err // retried
+	}
+	return retry.Permanent(err)
+*/
+package retrysys
+
+import "errors"
+
+/*
+	Transient reports whether err is caused by a system call failing in a
+	way that is likely to go away if it is made again: an interrupted call,
+	a resource temporarily unavailable, or a connection that was reset,
+	aborted, refused, timed out, or whose pipe broke. Which errors count
+	depends on the operating system:
+
+		Unix     EINTR, EAGAIN, EWOULDBLOCK, ECONNRESET, ECONNABORTED,
+		         ECONNREFUSED, ETIMEDOUT, EPIPE, EHOSTUNREACH, ENETUNREACH,
+		         ENETDOWN
+		Windows  WSAEINTR, WSAEWOULDBLOCK, WSAECONNRESET, WSAECONNABORTED,
+		         WSAECONNREFUSED, WSAETIMEDOUT, WSAEHOSTUNREACH, WSAENETUNREACH,
+		         WSAENETDOWN, ERROR_BROKEN_PIPE, ERROR_NO_DATA,
+		         ERROR_NETNAME_DELETED
+
+	No errors are recognised on other operating systems.
+*/
+func Transient(err error) bool {
+	if err == nil {
+		return false
+	}
+	for _, errno := range transient {
+		if errors.Is(err, errno) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,42 @@
+package retrysys
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"testing"
+)
+
+func TestTransient(t *testing.T) {
+
+	cases := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{errors.New("test"), false},
+		{fs.ErrNotExist, false},
+	}
+
+	for _, c := range cases {
+		if got := Transient(c.err); got != c.want {
+			t.Errorf("Transient(%v) = %t, wanted %t", c.err, got, c.want)
+		}
+	}
+
+	// Every recognised errno is transient however it is wrapped.
+	for _, errno := range transient {
+		wrapped := []error{
+			errno,
+			fmt.Errorf("write: %w", errno),
+			&net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", errno)},
+		}
+		for _, err := range wrapped {
+			if !Transient(err) {
+				t.Errorf("Transient(%v) = false, wanted true", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,8 @@
+//go:build !unix && !windows
+
+package retrysys
+
+/*
+	Transient errors aren't recognised on other operating systems.
+*/
+var transient []error
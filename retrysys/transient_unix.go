@@ -0,0 +1,19 @@
+//go:build unix
+
+package retrysys
+
+import "syscall"
+
+var transient = []error{
+	syscall.EINTR,
+	syscall.EAGAIN,
+	syscall.EWOULDBLOCK,
+	syscall.ECONNRESET,
+	syscall.ECONNABORTED,
+	syscall.ECONNREFUSED,
+	syscall.ETIMEDOUT,
+	syscall.EPIPE,
+	syscall.EHOSTUNREACH,
+	syscall.ENETUNREACH,
+	syscall.ENETDOWN,
+}
@@ -0,0 +1,34 @@
+//go:build windows
+
+package retrysys
+
+import "syscall"
+
+/*
+	Windows Sockets and system error codes missing from package syscall.
+*/
+const (
+	errorNoData     syscall.Errno = 232
+	wsaeintr        syscall.Errno = 10004
+	wsaewouldblock  syscall.Errno = 10035
+	wsaenetdown     syscall.Errno = 10050
+	wsaenetunreach  syscall.Errno = 10051
+	wsaetimedout    syscall.Errno = 10060
+	wsaeconnrefused syscall.Errno = 10061
+	wsaehostunreach syscall.Errno = 10065
+)
+
+var transient = []error{
+	wsaeintr,
+	wsaewouldblock,
+	syscall.WSAECONNRESET,
+	syscall.WSAECONNABORTED,
+	wsaeconnrefused,
+	wsaetimedout,
+	wsaehostunreach,
+	wsaenetunreach,
+	wsaenetdown,
+	syscall.ERROR_BROKEN_PIPE,
+	errorNoData,
+	syscall.ERROR_NETNAME_DELETED,
+}
@@ -0,0 +1,152 @@
+/*
+Package retrywait converts between retry.Options and the Backoff type of
+k8s.io/apimachinery/pkg/util/wait, so controllers and operators can define
+their retry policies once.
+
+Backoff here has the same fields as wait.Backoff, so the two convert with
+an ordinary type conversion without this module depending on apimachinery:
+
+	o := retrywait.ToOptions(retrywait.Backoff(wait.Backoff{
+		Duration: time.Millisecond * 100,
+		Factor:   2,
+		Jitter:   0.1,
+		Steps:    5,
+		Cap:      time.Second * 10,
+	}))
+
+	wb := wait.Backoff(retrywait.FromOptions(o))
+*/
+package retrywait
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/jakebowkett/retry"
+)
+
+/*
+	Backoff has the same fields, in the same order, as wait.Backoff.
+*/
+type Backoff struct {
+	Duration time.Duration
+	Factor   float64
+	Jitter   float64
+	Steps    int
+	Cap      time.Duration
+}
+
+/*
+	ToOptions returns Options matching b as closely as possible.
+
+	In wait.Backoff jitter lengthens a delay d to somewhere between d and
+	d*(1+Jitter), while Options.Jitter shortens it, so Base and MaxInterval
+	are raised to Duration*(1+Jitter) and Cap*(1+Jitter) and Jitter scaled
+	so the range of delays is the same. A Cap of 0 means no cap. Steps
+	counts every attempt, so Retries is one less. Since wait.Backoff has
+	no overall time limit MaxWait is set to the largest possible duration.
+*/
+func ToOptions(b Backoff) retry.Options {
+
+	factor := b.Factor
+	if factor < 1 {
+		factor = 1
+	}
+
+	jitter := 0.0
+	base := float64(b.Duration)
+	if b.Jitter > 0 {
+		jitter = b.Jitter / (1 + b.Jitter)
+		base *= 1 + b.Jitter
+	}
+
+	maxInterval := time.Duration(math.MaxInt64)
+	if b.Cap > 0 {
+		if c := float64(b.Cap) * (1 + max(b.Jitter, 0)); c < math.MaxInt64 {
+			maxInterval = time.Duration(c)
+		}
+	}
+
+	retries := b.Steps - 1
+	if retries < 0 {
+		retries = 0
+	}
+
+	return retry.Options{
+		Retries:     retries,
+		Base:        time.Duration(base),
+		MaxInterval: maxInterval,
+		MaxWait:     math.MaxInt64,
+		Exponent:    factor,
+		Jitter:      jitter,
+	}
+}
+
+/*
+	FromOptions returns a Backoff matching o as closely as possible. It is
+	the inverse of ToOptions, except that wait.Backoff has no equivalent of
+	MaxWait so it is dropped.
+*/
+func FromOptions(o retry.Options) Backoff {
+
+	duration := float64(o.Base)
+	limit := o.MaxInterval
+	jitter := 0.0
+	if o.Jitter > 0 && o.Jitter < 1 {
+		jitter = o.Jitter / (1 - o.Jitter)
+		duration *= 1 - o.Jitter
+		if limit != math.MaxInt64 {
+			limit = time.Duration(float64(limit) * (1 - o.Jitter))
+		}
+	}
+
+	return Backoff{
+		Duration: time.Duration(duration),
+		Factor:   o.Exponent,
+		Jitter:   jitter,
+		Steps:    o.RetryLimit() + 1,
+		Cap:      limit,
+	}
+}
+
+/*
+	ErrWaitTimeout is returned by ExponentialBackoffWithContext when the
+	condition never reports done before t gives up.
+*/
+var ErrWaitTimeout = errors.New("timed out waiting for the condition")
+
+/*
+	ExponentialBackoffWithContext mirrors the function of the same name in
+	package wait, with the backoff of t. It calls condition until it
+	reports done, returns an error, or t gives up. An error from condition
+	is returned as is, without further attempts. If t gives up or ctx is
+	done first, the returned error wraps both ErrWaitTimeout and the error
+	from t.
+*/
+func ExponentialBackoffWithContext(ctx context.Context, t *retry.Tryer, condition func(ctx context.Context) (done bool, err error)) error {
+
+	var condErr error
+	_, err := t.TryContext(ctx, func(ctx context.Context) error {
+		done, err := condition(ctx)
+		switch {
+		case err != nil:
+			condErr = err
+			return retry.Permanent(err)
+		case !done:
+			return errNotDone
+		}
+		return nil
+	})
+
+	switch {
+	case err == nil:
+		return nil
+	case condErr != nil:
+		return condErr
+	}
+	return errors.Join(ErrWaitTimeout, err)
+}
+
+var errNotDone = errors.New("condition not done")
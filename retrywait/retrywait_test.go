@@ -0,0 +1,89 @@
+package retrywait
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jakebowkett/retry"
+)
+
+func TestConvert(t *testing.T) {
+
+	b := Backoff{
+		Duration: time.Millisecond * 100,
+		Factor:   2,
+		Jitter:   1,
+		Steps:    5,
+		Cap:      time.Second * 10,
+	}
+
+	o := ToOptions(b)
+	if o.Base != time.Millisecond*200 || o.Jitter != 0.5 || o.Retries != 4 || o.Exponent != 2 {
+		t.Errorf("ToOptions(%+v) = %+v, wanted Base 200ms, Jitter 0.5, Retries 4, Exponent 2", b, o)
+	}
+	if _, err := retry.New(nil, o); err != nil {
+		t.Errorf("retry.New(nil, ToOptions(%+v)) returned error %v, wanted nil", b, err)
+	}
+
+	if got := FromOptions(o); got != b {
+		t.Errorf("FromOptions(ToOptions(%+v)) = %+v", b, got)
+	}
+
+	o.Retries, o.MaxAttempts = 0, 5
+	if got := FromOptions(o); got != b {
+		t.Errorf("FromOptions(%+v) = %+v, wanted %+v", o, got, b)
+	}
+
+	// A Cap equal to Duration still gives Options that New accepts.
+	b = Backoff{
+		Duration: time.Second,
+		Factor:   2,
+		Jitter:   0.1,
+		Steps:    3,
+		Cap:      time.Second,
+	}
+	if _, err := retry.New(nil, ToOptions(b)); err != nil {
+		t.Errorf("retry.New(nil, ToOptions(%+v)) returned error %v, wanted nil", b, err)
+	}
+}
+
+func TestExponentialBackoffWithContext(t *testing.T) {
+
+	tryer, err := retry.New(nil, ToOptions(Backoff{
+		Duration: time.Millisecond,
+		Factor:   2,
+		Steps:    3,
+	}))
+	if err != nil {
+		t.Fatal("Failed to initialise Tryer:\n    ", err.Error())
+	}
+
+	errBroken := errors.New("broken")
+
+	cases := []struct {
+		done    int // attempt on which the condition is done
+		fail    bool
+		wantErr error
+	}{
+		{2, false, nil},
+		{4, false, ErrWaitTimeout},
+		{0, true, errBroken},
+	}
+
+	for _, c := range cases {
+		n := 0
+		err := ExponentialBackoffWithContext(context.Background(), tryer, func(context.Context) (bool, error) {
+			n++
+			if c.fail {
+				return false, errBroken
+			}
+			return n == c.done, nil
+		})
+		if !errors.Is(err, c.wantErr) || c.wantErr == nil && err != nil {
+			t.Errorf("ExponentialBackoffWithContext done on attempt %d returned %v, wanted %v",
+				c.done, err, c.wantErr)
+		}
+	}
+}
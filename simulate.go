@@ -0,0 +1,134 @@
+package retry
+
+import (
+	"errors"
+	"slices"
+	"time"
+)
+
+/*
+	FailureModel describes a failing dependency for Simulate. It reports
+	whether an attempt made at the given time since the simulation began
+	fails.
+*/
+type FailureModel = func(at time.Duration) (fails bool)
+
+/*
+	Outage returns a FailureModel where every attempt fails for the first
+	d of the simulation and every attempt succeeds afterwards.
+*/
+func Outage(d time.Duration) FailureModel {
+	return func(at time.Duration) bool {
+		return at < d
+	}
+}
+
+/*
+	Simulation is the result of Simulate.
+*/
+type Simulation struct {
+	/*
+		Attempts holds the time of every attempt made by every client,
+		measured from the start of the simulation, in ascending order.
+	*/
+	Attempts []time.Duration
+
+	/*
+		Succeeded is the number of clients whose operation succeeded.
+	*/
+	Succeeded int
+
+	/*
+		GaveUp is the number of clients that stopped retrying because
+		they reached Options.Retries or Options.MaxWait.
+	*/
+	GaveUp int
+}
+
+/*
+	Rate returns the number of attempts made in each interval of the given
+	width, starting from the beginning of the simulation. It shows the load
+	the clients put on the dependency over time.
+*/
+func (s Simulation) Rate(width time.Duration) []int {
+	if len(s.Attempts) == 0 || width <= 0 {
+		return nil
+	}
+	rate := make([]int, s.Attempts[len(s.Attempts)-1]/width+1)
+	for _, at := range s.Attempts {
+		rate[at/width]++
+	}
+	return rate
+}
+
+var errSimulated = errors.New("simulated failure")
+
+/*
+	Simulate models the given number of clients all starting an operation
+	at the same moment against a dependency that fails as described by
+	failures, with each client retrying according to o. It runs in virtual
+	time, so it returns straight away, and attempts are treated as taking
+	no time. This helps choose values for Exponent, Jitter, and MaxInterval
+	before deploying them, for example:
+
+		sim, err := retry.Simulate(o, 1000, retry.Outage(time.Second*5))
+		for i, n := range sim.Rate(time.Millisecond * 100) {
+			fmt.Printf("%5dms %d\n", i*100, n)
+		}
+
+	Options.Backoff, Seed, and Source are used, but the hooks, Adaptive,
+	Throttle, and Coordinator are ignored. Simulate returns an error if o
+	is invalid in the same way as for New.
+*/
+func Simulate(o Options, clients int, failures FailureModel) (Simulation, error) {
+
+	var sim Simulation
+
+	c, err := newConfig(o)
+	if err != nil {
+		return sim, err
+	}
+
+	for i := 0; i < clients; i++ {
+
+		var backoff Backoff
+		if o.Backoff != nil {
+			backoff = o.Backoff()
+		}
+
+		var at time.Duration
+		for attempt := 0; ; attempt++ {
+
+			sim.Attempts = append(sim.Attempts, at)
+			if !failures(at) {
+				sim.Succeeded++
+				break
+			}
+			if attempt >= c.retries {
+				sim.GaveUp++
+				break
+			}
+
+			var sleep time.Duration
+			if backoff != nil {
+				d, ok := backoff.Next(errSimulated)
+				if !ok {
+					sim.GaveUp++
+					break
+				}
+				sleep = d
+			} else {
+				sleep = c.delay(attempt)
+			}
+
+			if at+sleep > c.maxWait {
+				sim.GaveUp++
+				break
+			}
+			at += sleep
+		}
+	}
+
+	slices.Sort(sim.Attempts)
+	return sim, nil
+}
@@ -0,0 +1,59 @@
+package retry
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSimulate(t *testing.T) {
+
+	o := Options{
+		Retries:     10,
+		Base:        time.Millisecond * 100,
+		MaxInterval: time.Second * 1,
+		MaxWait:     time.Second * 5,
+		Exponent:    2,
+		Jitter:      0.5,
+		Seed:        1,
+	}
+
+	cases := []struct {
+		failures      FailureModel
+		wantSucceeded int
+		wantGaveUp    int
+	}{
+		{Outage(0), 100, 0},
+		{Outage(time.Second * 2), 100, 0},
+		{Outage(time.Minute), 0, 100},
+	}
+
+	for _, c := range cases {
+
+		sim, err := Simulate(o, 100, c.failures)
+		if err != nil {
+			t.Fatal("Failed to simulate:\n    ", err.Error())
+		}
+		if sim.Succeeded != c.wantSucceeded || sim.GaveUp != c.wantGaveUp {
+			t.Errorf("Simulate returned %d succeeded and %d gave up, wanted %d and %d",
+				sim.Succeeded, sim.GaveUp, c.wantSucceeded, c.wantGaveUp)
+		}
+
+		total := 0
+		for _, n := range sim.Rate(time.Millisecond * 100) {
+			total += n
+		}
+		if total != len(sim.Attempts) {
+			t.Errorf("Rate counted %d attempts, wanted %d", total, len(sim.Attempts))
+		}
+	}
+
+	// Every client makes its first attempt at once.
+	sim, _ := Simulate(o, 100, Outage(time.Second))
+	if rate := sim.Rate(time.Millisecond * 10); rate[0] != 100 {
+		t.Errorf("Rate()[0] = %d, wanted all 100 clients", rate[0])
+	}
+
+	if _, err := Simulate(Options{}, 1, Outage(0)); err == nil {
+		t.Errorf("Simulate with invalid Options returned nil error, wanted error")
+	}
+}
@@ -0,0 +1,88 @@
+package retry
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+/*
+	SlowStart ramps retries back up gradually after a dependency recovers
+	from an outage, instead of releasing every pending retry onto it at
+	once. An outage is a run of consecutive failed attempts at least as
+	long as the threshold given to NewSlowStart. When an attempt next
+	succeeds the ramp begins: each retry is allowed with a probability
+	that rises linearly from 0 to 1 over the ramp duration, and retries
+	that aren't allowed stop with ErrThrottled. First attempts are never
+	held back.
+
+	A SlowStart is safe for concurrent use and is usually shared by all
+	the Tryers calling one dependency. Attach it with Options.SlowStart.
+	Code that learns of a recovery another way, such as a circuit breaker
+	closing, can begin the ramp with Recovered.
+*/
+type SlowStart struct {
+	threshold int
+	ramp      time.Duration
+	mu        sync.Mutex
+	failures  int
+	recovered time.Time
+}
+
+/*
+	NewSlowStart returns a SlowStart that treats threshold consecutive
+	failed attempts as an outage and ramps retries up over ramp once it
+	ends. Both must be greater than 0.
+*/
+func NewSlowStart(threshold int, ramp time.Duration) (*SlowStart, error) {
+
+	if threshold <= 0 {
+		return nil, fmt.Errorf("expected threshold to be greater than 0, got %d", threshold)
+	}
+
+	if ramp <= 0 {
+		return nil, fmt.Errorf("expected ramp to be greater than 0, got %s", ramp)
+	}
+
+	return &SlowStart{threshold: threshold, ramp: ramp}, nil
+}
+
+/*
+	Recovered begins the ramp now, as though an outage had just ended.
+*/
+func (ss *SlowStart) Recovered() {
+	ss.mu.Lock()
+	ss.failures = 0
+	ss.recovered = time.Now()
+	ss.mu.Unlock()
+}
+
+/*
+	Allow reports whether a retry may go ahead. Outside a ramp it is always
+	true.
+*/
+func (ss *SlowStart) Allow() bool {
+
+	ss.mu.Lock()
+	since := time.Since(ss.recovered)
+	ss.mu.Unlock()
+
+	if since >= ss.ramp {
+		return true
+	}
+	return rand.Float64() < float64(since)/float64(ss.ramp)
+}
+
+func (ss *SlowStart) record(err error) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	if err != nil {
+		ss.failures++
+		return
+	}
+	if ss.failures >= ss.threshold {
+		ss.recovered = time.Now()
+	}
+	ss.failures = 0
+}
@@ -0,0 +1,71 @@
+package retry
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSlowStart(t *testing.T) {
+
+	if _, err := NewSlowStart(0, time.Second); err == nil {
+		t.Errorf("NewSlowStart(0, 1s) returned nil error, wanted error")
+	}
+	if _, err := NewSlowStart(3, 0); err == nil {
+		t.Errorf("NewSlowStart(3, 0) returned nil error, wanted error")
+	}
+
+	// A long ramp keeps the checks of held back retries from depending
+	// on how quickly the test runs.
+	ss, err := NewSlowStart(3, time.Minute)
+	if err != nil {
+		t.Fatal("Failed to initialise SlowStart:\n    ", err.Error())
+	}
+
+	// Before any outage retries are allowed.
+	if !ss.Allow() {
+		t.Errorf("Allow() before an outage = false, wanted true")
+	}
+
+	// Too few failures aren't an outage.
+	errTest := errors.New("test")
+	ss.record(errTest)
+	ss.record(errTest)
+	ss.record(nil)
+	if !ss.Allow() {
+		t.Errorf("Allow() after a short run of failures = false, wanted true")
+	}
+
+	// Just after an outage ends nearly every retry is held back.
+	ss.record(errTest)
+	ss.record(errTest)
+	ss.record(errTest)
+	ss.record(nil)
+	allowed := 0
+	for i := 0; i < 100; i++ {
+		if ss.Allow() {
+			allowed++
+		}
+	}
+	if allowed > 10 {
+		t.Errorf("Allow() just after an outage was true %d times in 100, wanted few", allowed)
+	}
+
+	ss.Recovered()
+	if ss.Allow() && ss.Allow() && ss.Allow() && ss.Allow() && ss.Allow() {
+		t.Errorf("Allow() just after Recovered was always true, wanted retries held back")
+	}
+
+	// After the ramp every retry is allowed.
+	ss, err = NewSlowStart(3, time.Millisecond*10)
+	if err != nil {
+		t.Fatal("Failed to initialise SlowStart:\n    ", err.Error())
+	}
+	ss.Recovered()
+	time.Sleep(time.Millisecond * 10)
+	for i := 0; i < 100; i++ {
+		if !ss.Allow() {
+			t.Fatalf("Allow() after the ramp = false, wanted true")
+		}
+	}
+}
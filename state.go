@@ -0,0 +1,39 @@
+package retry
+
+import "time"
+
+/*
+	State describes how far a call to Try or TryContext has progressed. It
+	is reported to Options.OnRetry and can be marshalled, stored, and passed
+	to Tryer.Resume to continue the call after a restart rather than begin
+	again from the first attempt.
+*/
+type State struct {
+	/*
+		Attempts is the number of attempts made so far.
+	*/
+	Attempts int `json:"attempts"`
+
+	/*
+		Elapsed is the time spent on the call so far.
+	*/
+	Elapsed time.Duration `json:"elapsed"`
+
+	/*
+		Waited is the time spent waiting between attempts so far.
+	*/
+	Waited time.Duration `json:"waited"`
+
+	/*
+		NextDelay is the time to wait before the next attempt.
+	*/
+	NextDelay time.Duration `json:"nextDelay"`
+
+	/*
+		History is the failed attempts made so far by the current call,
+		including the attempt that has just failed. It isn't marshalled.
+		OnRetry is given a copy it may keep, in which the last attempt has
+		no Delay as the wait after it hasn't happened yet.
+	*/
+	History History `json:"-"`
+}
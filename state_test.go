@@ -0,0 +1,61 @@
+package retry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestResume(t *testing.T) {
+
+	var saved []byte
+
+	tryer, err := New(nil, Options{
+		Retries:     3,
+		Base:        time.Millisecond * 5,
+		MaxInterval: time.Millisecond * 20,
+		MaxWait:     time.Second * 1,
+		Exponent:    2,
+		OnRetry: func(s State, err error) {
+			saved, _ = json.Marshal(s)
+		},
+	})
+	if err != nil {
+		t.Fatal("Failed to initialise Tryer while testing method Resume:\n    ", err.Error())
+	}
+
+	// Simulate the process stopping after the second attempt.
+	ctx, cancel := context.WithCancel(context.Background())
+	_, _ = tryer.TryContext(ctx, func(ctx context.Context) error {
+		if n, _ := AttemptFromContext(ctx); n == 2 {
+			cancel()
+		}
+		return errors.New("test")
+	})
+
+	var s State
+	if err := json.Unmarshal(saved, &s); err != nil {
+		t.Fatal("Failed to unmarshal State:\n    ", err.Error())
+	}
+	if s.Attempts != 1 || s.NextDelay <= 0 {
+		t.Fatalf("saved State = %+v, wanted 1 attempt and a positive NextDelay", s)
+	}
+
+	var got []int
+	errs, err := tryer.Resume(context.Background(), s, func(ctx context.Context) error {
+		n, _ := AttemptFromContext(ctx)
+		got = append(got, n)
+		return errors.New("test")
+	})
+
+	if len(errs) != 3 || got[0] != 2 || got[2] != 4 {
+		t.Errorf("Tryer.Resume made attempts %v, wanted [2 3 4]", got)
+	}
+
+	var re *Error
+	if !errors.As(err, &re) || !errors.Is(err, ErrMaxRetries) || re.Attempts() != 4 {
+		t.Errorf("Tryer.Resume returned %v, wanted ErrMaxRetries after 4 attempts", err)
+	}
+}
@@ -0,0 +1,66 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+/*
+	Summary describes the outcome of a call to TrySummary, so callers can
+	log or record metrics for an operation in a single line.
+*/
+type Summary struct {
+	/*
+		Attempts is the number of times the operation was called.
+	*/
+	Attempts int
+
+	/*
+		Elapsed is the total time spent on the call, including waiting.
+	*/
+	Elapsed time.Duration
+
+	/*
+		Waited is the time spent waiting between attempts.
+	*/
+	Waited time.Duration
+
+	/*
+		Succeeded reports whether an attempt succeeded.
+	*/
+	Succeeded bool
+
+	/*
+		Err is the overall error TryContext would have returned, or nil if
+		an attempt succeeded.
+	*/
+	Err error
+}
+
+/*
+	String returns s as a line suitable for logging, such as:
+
+		succeeded after 3 attempt(s) in 152ms (waited 150ms)
+*/
+func (s Summary) String() string {
+	if s.Succeeded {
+		return fmt.Sprintf("succeeded after %d attempt(s) in %s (waited %s)",
+			s.Attempts, s.Elapsed, s.Waited)
+	}
+	return fmt.Sprintf("failed after %d attempt(s) in %s (waited %s): %v",
+		s.Attempts, s.Elapsed, s.Waited, s.Err)
+}
+
+/*
+	TrySummary is like TryContext but returns a Summary of the call in
+	place of the errors from each attempt.
+*/
+func (t *Tryer) TrySummary(ctx context.Context, fn OperationCtx) Summary {
+	if fn == nil {
+		return Summary{Err: errNoFunc}
+	}
+	var sum Summary
+	t.try(ctx, fn, nil, State{}, &sum)
+	return sum
+}
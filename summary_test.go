@@ -0,0 +1,58 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTrySummary(t *testing.T) {
+
+	tryer, err := New(nil, Options{
+		Retries:     2,
+		Base:        time.Millisecond * 5,
+		MaxInterval: time.Millisecond * 5,
+		MaxWait:     time.Second * 1,
+		Exponent:    1,
+	})
+	if err != nil {
+		t.Fatal("Failed to initialise Tryer while testing TrySummary:\n    ", err.Error())
+	}
+
+	cases := []struct {
+		failures      int // attempts that fail before succeeding
+		wantAttempts  int
+		wantSucceeded bool
+		wantErr       error
+		wantPrefix    string
+	}{
+		{0, 1, true, nil, "succeeded after 1 attempt(s)"},
+		{1, 2, true, nil, "succeeded after 2 attempt(s)"},
+		{5, 3, false, ErrMaxRetries, "failed after 3 attempt(s)"},
+	}
+
+	for _, c := range cases {
+
+		calls := 0
+		sum := tryer.TrySummary(context.Background(), func(context.Context) error {
+			calls++
+			if calls <= c.failures {
+				return errors.New("test")
+			}
+			return nil
+		})
+
+		wantWaited := time.Millisecond * 5 * time.Duration(c.wantAttempts-1)
+		if sum.Attempts != c.wantAttempts || sum.Succeeded != c.wantSucceeded ||
+			!errors.Is(sum.Err, c.wantErr) || c.wantErr == nil && sum.Err != nil ||
+			sum.Waited != wantWaited || sum.Elapsed < sum.Waited {
+			t.Errorf("TrySummary failing %d times returned %+v, wanted %d attempts, succeeded %t, waited %s, error %v",
+				c.failures, sum, c.wantAttempts, c.wantSucceeded, wantWaited, c.wantErr)
+		}
+		if !strings.HasPrefix(sum.String(), c.wantPrefix) {
+			t.Errorf("Summary.String() = %q, wanted prefix %q", sum.String(), c.wantPrefix)
+		}
+	}
+}
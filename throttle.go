@@ -0,0 +1,132 @@
+package retry
+
+import (
+	"fmt"
+	"sync"
+)
+
+/*
+	Throttle suppresses retries client side while a dependency is failing,
+	using the token scheme from gRPC's retry throttling design. It holds a
+	number of tokens, initially its maximum. Each attempt that fails with
+	an error Try would retry removes one token, and each successful
+	attempt adds a fraction of a token. Permanent errors and errors the
+	Retry given to New rejects cost nothing, so a dependency answering
+	many requests with errors such as not found doesn't throttle others.
+	Retries are only allowed while more than half the maximum tokens
+	remain, so retries stop once failures clearly outweigh successes and
+	resume as the dependency recovers. Calls tagged with a Priority use
+	a different threshold; see AllowPriority.
+
+	A Throttle is safe for concurrent use and is usually shared by all the
+	Tryers calling one dependency. Attach it with Options.Throttle.
+*/
+type Throttle struct {
+	max    float64
+	ratio  float64
+	mu     sync.Mutex
+	tokens float64
+}
+
+/*
+	NewThrottle returns a Throttle holding maxTokens tokens that gains
+	tokenRatio tokens for each successful attempt. As in gRPC, maxTokens
+	must be greater than 0 and at most 1000, and tokenRatio must be
+	greater than 0 and at most 1.
+*/
+func NewThrottle(maxTokens, tokenRatio float64) (*Throttle, error) {
+
+	if maxTokens <= 0 || maxTokens > 1000 {
+		return nil, fmt.Errorf("expected maxTokens to be greater than 0 and at most 1000, got %.2f", maxTokens)
+	}
+
+	if tokenRatio <= 0 || tokenRatio > 1 {
+		return nil, fmt.Errorf("expected tokenRatio to be greater than 0 and at most 1, got %.2f", tokenRatio)
+	}
+
+	return &Throttle{max: maxTokens, ratio: tokenRatio, tokens: maxTokens}, nil
+}
+
+/*
+	Tokens returns the number of tokens the Throttle currently holds.
+*/
+func (th *Throttle) Tokens() float64 {
+	th.mu.Lock()
+	defer th.mu.Unlock()
+	return th.tokens
+}
+
+/*
+	Allow reports whether retries with PriorityNormal are currently allowed.
+*/
+func (th *Throttle) Allow() bool {
+	return th.AllowPriority(PriorityNormal)
+}
+
+/*
+	AllowPriority reports whether retries with priority p are currently
+	allowed. Normal priority retries need more than half the maximum
+	tokens, low priority retries more than three quarters, and high
+	priority retries more than a quarter. This sheds background retries
+	first while user facing work keeps retrying for longer.
+*/
+func (th *Throttle) AllowPriority(p Priority) bool {
+
+	threshold := 0.5
+	switch {
+	case p < PriorityNormal:
+		threshold = 0.75
+	case p > PriorityNormal:
+		threshold = 0.25
+	}
+
+	th.mu.Lock()
+	defer th.mu.Unlock()
+	return th.tokens > th.max*threshold
+}
+
+/*
+	Record counts the result of an attempt made outside a Tryer, such as
+	by a hand written loop or another library, so that code shares the
+	Throttle's accounting with the Tryers using it. A nil err counts as a
+	success and any other err as a failure, so only errors that would be
+	retried should be recorded. Such code should also check Allow or
+	AllowPriority before each retry:
+
+		for {
+			err := call()
+			if err != nil && !retryable(err) {
+				return err
+			}
+			th.Record(err)
+			if err == nil || !th.Allow() {
+				return err
+			}
+			time.Sleep(delay)
+		}
+*/
+func (th *Throttle) Record(err error) {
+	if err == nil {
+		th.success()
+	} else {
+		th.failure()
+	}
+}
+
+func (th *Throttle) failure() {
+	th.mu.Lock()
+	th.tokens--
+	if th.tokens < 0 {
+		th.tokens = 0
+	}
+	th.mu.Unlock()
+}
+
+func (th *Throttle) success() {
+	th.mu.Lock()
+	th.tokens += th.ratio
+	if th.tokens > th.max {
+		th.tokens = th.max
+	}
+	th.mu.Unlock()
+}
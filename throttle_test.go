@@ -0,0 +1,118 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestThrottle(t *testing.T) {
+
+	th, err := NewThrottle(4, 0.5)
+	if err != nil {
+		t.Fatal("Failed to initialise Throttle:\n    ", err.Error())
+	}
+
+	tryer, err := New(nil, Options{
+		Retries:     5,
+		Base:        time.Millisecond,
+		MaxInterval: time.Millisecond * 5,
+		MaxWait:     time.Second * 1,
+		Exponent:    2,
+		Throttle:    th,
+	})
+	if err != nil {
+		t.Fatal("Failed to initialise Tryer while testing Throttle:\n    ", err.Error())
+	}
+
+	// Tokens fall from 4 to 2 after two failures, which
+	// is no longer more than half so retries stop.
+	errs, err := tryer.Try(func() error {
+		return errors.New("test")
+	})
+	if !errors.Is(err, ErrThrottled) || len(errs) != 2 {
+		t.Errorf("Tryer.Try returned %v after %d attempts, wanted %v after 2",
+			err, len(errs), ErrThrottled)
+	}
+
+	// Successes earn tokens back.
+	_, _ = tryer.Try(func() error { return nil })
+	if got := th.Tokens(); got != 2.5 || !th.Allow() {
+		t.Errorf("Throttle.Tokens() after success = %.2f, wanted 2.50 and retries allowed", got)
+	}
+
+	// At 2.5 of 4 tokens low priority retries are shed
+	// while high priority ones are still allowed.
+	lowErrs, err := tryer.TryContext(WithPriority(context.Background(), PriorityLow), func(context.Context) error {
+		return errors.New("test")
+	})
+	if !errors.Is(err, ErrThrottled) || len(lowErrs) != 1 {
+		t.Errorf("low priority Tryer.TryContext returned %v after %d attempts, wanted %v after 1",
+			err, len(lowErrs), ErrThrottled)
+	}
+	if !th.AllowPriority(PriorityHigh) {
+		t.Errorf("Throttle.AllowPriority(PriorityHigh) at %.2f tokens = false, wanted true", th.Tokens())
+	}
+
+	for _, c := range [][2]float64{{0, 0.5}, {1001, 0.5}, {10, 0}, {10, 1.5}} {
+		if _, err := NewThrottle(c[0], c[1]); err == nil {
+			t.Errorf("NewThrottle(%.2f, %.2f) returned nil error, wanted error", c[0], c[1])
+		}
+	}
+}
+
+func TestThrottleRecord(t *testing.T) {
+
+	th, err := NewThrottle(4, 0.5)
+	if err != nil {
+		t.Fatal("Failed to initialise Throttle:\n    ", err.Error())
+	}
+
+	th.Record(errors.New("test"))
+	th.Record(errors.New("test"))
+	if got := th.Tokens(); got != 2 {
+		t.Errorf("Tokens() after 2 failures = %.2f, wanted 2", got)
+	}
+	if th.Allow() {
+		t.Errorf("Allow() after 2 failures = true, wanted false")
+	}
+
+	th.Record(nil)
+	if got := th.Tokens(); got != 2.5 {
+		t.Errorf("Tokens() after a success = %.2f, wanted 2.5", got)
+	}
+	if !th.Allow() {
+		t.Errorf("Allow() after a success = false, wanted true")
+	}
+}
+
+func TestThrottlePermanent(t *testing.T) {
+
+	th, err := NewThrottle(4, 0.5)
+	if err != nil {
+		t.Fatal("Failed to initialise Throttle:\n    ", err.Error())
+	}
+
+	errNotFound := errors.New("not found")
+	tryer, err := New(func(err error) bool { return err != errNotFound }, Options{
+		Retries:     5,
+		Base:        time.Millisecond,
+		MaxInterval: time.Millisecond * 5,
+		MaxWait:     time.Second * 1,
+		Exponent:    2,
+		Throttle:    th,
+	})
+	if err != nil {
+		t.Fatal("Failed to initialise Tryer while testing Throttle:\n    ", err.Error())
+	}
+
+	// Errors that aren't retried don't cost tokens.
+	for i := 0; i < 10; i++ {
+		tryer.Try(func() error { return Permanent(errors.New("test")) })
+		tryer.Try(func() error { return errNotFound })
+	}
+	if got := th.Tokens(); got != 4 {
+		t.Errorf("Throttle.Tokens() after errors that aren't retried = %.2f, wanted 4", got)
+	}
+}
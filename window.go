@@ -0,0 +1,37 @@
+package retry
+
+import (
+	"sync"
+	"time"
+)
+
+/*
+	window counts the retries made by a Tryer in fixed windows of time
+	for Options.MaxRetriesPerWindow.
+*/
+type window struct {
+	mu    sync.Mutex
+	start time.Time
+	n     int
+}
+
+/*
+	allow reports whether another retry fits within limit for the current
+	window of the given length, counting it if so.
+*/
+func (w *window) allow(limit int, length time.Duration) bool {
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if now := time.Now(); now.Sub(w.start) >= length {
+		w.start = now
+		w.n = 0
+	}
+
+	if w.n >= limit {
+		return false
+	}
+	w.n++
+	return true
+}
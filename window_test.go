@@ -0,0 +1,87 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMaxRetriesPerWindow(t *testing.T) {
+
+	if _, err := New(nil, Options{Exponent: 2, MaxRetriesPerWindow: 5}); err == nil {
+		t.Errorf("New with MaxRetriesPerWindow but no Window returned nil error, wanted error")
+	}
+
+	tryer, err := New(nil, Options{
+		Retries:             5,
+		Base:                time.Millisecond * 1,
+		MaxInterval:         time.Millisecond * 1,
+		MaxWait:             time.Second * 1,
+		Exponent:            1,
+		MaxRetriesPerWindow: 3,
+		Window:              time.Millisecond * 200,
+	})
+	if err != nil {
+		t.Fatal("Failed to initialise Tryer while testing MaxRetriesPerWindow:\n    ", err.Error())
+	}
+
+	fail := func() error { return errors.New("test") }
+
+	// The first call uses two of the three retries in the window.
+	calls := 0
+	tryer.Try(func() error {
+		calls++
+		if calls < 3 {
+			return errors.New("test")
+		}
+		return nil
+	})
+
+	// The second call gets the last retry before being throttled.
+	errs, err := tryer.Try(fail)
+	if !errors.Is(err, ErrThrottled) || len(errs) != 2 {
+		t.Errorf("Try returned %v after %d attempts, wanted %v after 2", err, len(errs), ErrThrottled)
+	}
+
+	time.Sleep(time.Millisecond * 200)
+
+	// A new window allows retries again.
+	errs, err = tryer.Try(fail)
+	if !errors.Is(err, ErrThrottled) || len(errs) != 4 {
+		t.Errorf("Try in a new window returned %v after %d attempts, wanted %v after 4", err, len(errs), ErrThrottled)
+	}
+}
+
+func TestMaxRetriesPerWindowAbandoned(t *testing.T) {
+
+	healthy := false
+	tryer, err := New(nil, Options{
+		Retries:             5,
+		Base:                time.Millisecond * 1,
+		MaxInterval:         time.Millisecond * 1,
+		MaxWait:             time.Second * 1,
+		Exponent:            1,
+		Health:              func(context.Context) bool { return healthy },
+		MaxRetriesPerWindow: 3,
+		Window:              time.Minute,
+	})
+	if err != nil {
+		t.Fatal("Failed to initialise Tryer while testing MaxRetriesPerWindow:\n    ", err.Error())
+	}
+
+	fail := func() error { return errors.New("test") }
+
+	// Retries abandoned because the dependency is down don't use the window.
+	for i := 0; i < 5; i++ {
+		if _, err := tryer.Try(fail); !errors.Is(err, ErrUnhealthy) {
+			t.Fatalf("Try while unhealthy returned %v, wanted %v", err, ErrUnhealthy)
+		}
+	}
+
+	healthy = true
+	errs, err := tryer.Try(fail)
+	if !errors.Is(err, ErrThrottled) || len(errs) != 4 {
+		t.Errorf("Try returned %v after %d attempts, wanted %v after 4", err, len(errs), ErrThrottled)
+	}
+}